@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+)
+
+// tlsVersions maps --tls-min-version values to crypto/tls constants.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseCipherSuites resolves Go cipher suite names (e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256).
+// Only suites Go considers secure are accepted. TLS 1.3 suites are not configurable.
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	known := map[string]uint16{}
+	for _, cs := range tls.CipherSuites() {
+		known[cs.Name] = cs.ID
+	}
+	ids := make([]uint16, 0, len(names))
+	for _, n := range names {
+		id, ok := known[strings.ToUpper(n)]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %q", n)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[time.Duration]*http.Client{}
+	transport http.RoundTripper
+)
+
+// httpClient returns the shared client for timeout, enforcing the configured minimum
+// TLS version and cipher suites. All clients share one transport, so concurrent
+// per-PR calls reuse connections instead of doing a TLS handshake each.
+// A zero timeout means no timeout.
+func httpClient(cfg config, timeout time.Duration) *http.Client {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if transport == nil {
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = &tls.Config{MinVersion: cfg.TLSMinVersion, CipherSuites: cfg.TLSCipherSuites}
+		// Keep an idle connection for every call the pool may have in flight.
+		tr.MaxIdleConnsPerHost = max(cfg.MaxConcurrency, cfg.Concurrency, 2)
+		transport = azdo.RetryTransport{Base: tlsVersionTransport{base: tr, min: cfg.TLSMinVersion}}
+	}
+	c, ok := clients[timeout]
+	if !ok {
+		c = &http.Client{Timeout: timeout, Transport: transport}
+		clients[timeout] = c
+	}
+	return c
+}
+
+// tlsVersionTransport turns handshake failures caused by the TLS floor into a clear error.
+type tlsVersionTransport struct {
+	base http.RoundTripper
+	min  uint16
+}
+
+func (t tlsVersionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil && strings.Contains(err.Error(), "protocol version") {
+		return nil, fmt.Errorf("TLS handshake with %s failed: server does not support TLS %s or newer (see --tls-min-version): %w", req.URL.Host, tls.VersionName(t.min), err)
+	}
+	return resp, err
+}
+
+// maxTruncatedRetries is how often a request whose response body was cut short is retried.
+const maxTruncatedRetries = 2
+
+// truncatedRetryDelay is the backoff before the first such retry; it doubles with each one.
+var truncatedRetryDelay = time.Second
+
+// isTruncated reports whether err indicates a response body that ended prematurely,
+// which on flaky connections is worth retrying.
+func isTruncated(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var se *json.SyntaxError
+	return errors.As(err, &se) && strings.Contains(se.Error(), "unexpected end of JSON input")
+}
+
+// apiClient returns an API client for cfg's organization (or --base-url collection), API
+// version and --auth method.
+// timeout bounds each request; 0 means none.
+func apiClient(cfg config, timeout time.Duration) *azdo.Client {
+	return &azdo.Client{
+		BaseURL:    cfg.BaseURL,
+		Org:        cfg.Org,
+		APIVersion: cfg.ApiVer,
+		Authorize:  authorizer(cfg),
+		HTTP:       httpClient(cfg, 0),
+		Timeout:    timeout,
+	}
+}
+
+// apiGet performs an authenticated GET against endpoint and decodes the JSON response into v.
+// Non-2xx responses are returned as *httpStatusError.
+func apiGet(cfg config, endpoint string, v any) error {
+	return apiSend(cfg, http.MethodGet, endpoint, nil, v)
+}
+
+// apiSend performs an authenticated request with body (if not nil) encoded as JSON and
+// decodes the JSON response into v (if not nil). Non-2xx responses are returned as
+// *httpStatusError carrying the server's message when it sent one.
+func apiSend(cfg config, method, endpoint string, body, v any) error {
+	return apiClient(cfg, 15*time.Second).Send(context.Background(), method, endpoint, body, v)
+}
+
+// fetchPRWorkItems returns the IDs of the work items linked to pr.
+func fetchPRWorkItems(ctx context.Context, cfg config, pr pullRequest) ([]string, error) {
+	return apiClient(cfg, 15*time.Second).PullRequestWorkItems(ctx, prProject(cfg, pr), pr)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// shortApprovalID is how many characters of an approval's ID are shown; any unique
+// prefix is accepted as an argument.
+const shortApprovalID = 8
+
+// pendingApprovals lists the pending approvals of the project, only those with a step
+// assigned to me unless everyone is set. Steps assigned to a group I belong to don't
+// count as mine, since that would take resolving my group memberships.
+func pendingApprovals(cfg config, everyone bool) ([]azdo.Approval, error) {
+	approvals, err := apiClient(cfg, 15*time.Second).Approvals(context.Background(), cfg.Project, "pending")
+	if err != nil || everyone {
+		return approvals, err
+	}
+	me, err := resolveMe(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("resolving the current user: %w", err)
+	}
+	var mine []azdo.Approval
+	for _, a := range approvals {
+		for _, s := range a.Steps {
+			if me.is(s.AssignedApprover.ID, s.AssignedApprover.UniqueName) {
+				mine = append(mine, a)
+				break
+			}
+		}
+	}
+	return mine, nil
+}
+
+// pendingApprovers names the approvers of a who have not decided yet.
+func pendingApprovers(a azdo.Approval) string {
+	var names []string
+	for _, s := range a.Steps {
+		if s.Status == "pending" || s.Status == "" {
+			names = append(names, s.AssignedApprover.DisplayName)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// printApprovals writes one row per approval.
+func printApprovals(w io.Writer, cfg config, approvals []azdo.Approval) {
+	th := resolveTheme(cfg)
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(th.Style)
+	t.SetTitle(fmt.Sprintf("Pending approvals in %s", cfg.Project))
+	t.AppendHeader(table.Row{"ID", "Pipeline", "Run", "Waiting for", "Instructions", "Created", "URL"})
+	for _, a := range approvals {
+		t.AppendRow(table.Row{a.ID[:min(len(a.ID), shortApprovalID)], a.Pipeline.Name, a.Pipeline.Owner.Name,
+			pendingApprovers(a), truncate(a.Instructions, 60), relTime(cfg, a.CreatedOn.Time), a.Pipeline.Owner.Links.Web.Href})
+	}
+	t.Render()
+}
+
+// runApprovalsList runs "approvals list": the pending pipeline approvals assigned to me.
+func runApprovalsList(args []string) {
+	everyone := flag.Bool("everyone", false, "List every pending approval, including those assigned to your groups, not just those assigned to you")
+	cfg := getConfig(args)
+	if cfg.multiProject() {
+		failUsage("approvals list needs a single --project")
+	}
+	approvals, err := pendingApprovals(cfg, *everyone)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+
+	out, closeOut := openOutput(cfg)
+	defer closeOut()
+	switch {
+	case cfg.Output == outputJSON:
+		if err := printJSON(out, orEmpty(approvals)); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case len(approvals) == 0 && *everyone:
+		fmt.Fprintln(out, "No pending approvals.")
+	case len(approvals) == 0:
+		fmt.Fprintln(out, "No pending approvals assigned to you; approvals assigned to your groups are listed with --everyone.")
+	default:
+		printApprovals(out, cfg, approvals)
+	}
+}
+
+// decideApproval returns the command that sets a pending approval to status, i.e.
+// "approvals approve" (approved) and "approvals reject" (rejected).
+func decideApproval(command, status string) func(args []string) {
+	return func(args []string) {
+		comment := flag.String("comment", "", "Comment recorded with the decision")
+		cfg := getConfig(args)
+		if len(cfg.Args) != 1 {
+			failUsage(fmt.Sprintf("usage: lazydevops approvals %s <id> [--comment \"...\"]", command))
+		}
+		if cfg.multiProject() {
+			failUsage("approvals " + command + " needs a single --project")
+		}
+		approvals, err := pendingApprovals(cfg, true)
+		if err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		prefix := strings.ToLower(cfg.Args[0])
+		var matches []azdo.Approval
+		for _, a := range approvals {
+			if strings.HasPrefix(strings.ToLower(a.ID), prefix) {
+				matches = append(matches, a)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			log.Fatalf("Error: no pending approval %s in %s\n", cfg.Args[0], cfg.Project)
+		case 1:
+		default:
+			log.Fatalf("Error: %s matches %d pending approvals, give more of the ID\n", cfg.Args[0], len(matches))
+		}
+		a := matches[0]
+		if _, err := apiClient(cfg, 15*time.Second).UpdateApproval(context.Background(), cfg.Project, a.ID, status, *comment); err != nil {
+			log.Fatalf("Error: %s approval %s: %v\n", command, a.ID, err)
+		}
+		fmt.Printf("Set approval %s of %s %s to %s\n", a.ID[:min(len(a.ID), shortApprovalID)], a.Pipeline.Name, a.Pipeline.Owner.Name, status)
+	}
+}
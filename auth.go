@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+)
+
+// --auth values.
+const (
+	authPAT   = "pat"
+	authAzCLI = "azcli"
+)
+
+// azureDevOpsResource is the application ID of Azure DevOps in Microsoft Entra ID;
+// access tokens for its REST API are requested for this resource.
+const azureDevOpsResource = "499b84ac-1321-427f-aa17-267ca6975798"
+
+// authorizer returns how requests are authorized for the configured --auth method.
+func authorizer(cfg config) func(*http.Request) error {
+	if cfg.Auth == authAzCLI {
+		return azdo.Bearer(azCLIToken)
+	}
+	return azdo.PAT(cfg.Pat)
+}
+
+// azToken caches the Azure CLI access token across calls.
+var azToken struct {
+	mu      sync.Mutex
+	value   string
+	expires time.Time
+}
+
+// azCLIToken returns an access token from "az account get-access-token", requesting a
+// new one when the cached token expires within five minutes.
+func azCLIToken() (string, error) {
+	azToken.mu.Lock()
+	defer azToken.mu.Unlock()
+	if azToken.value != "" && time.Until(azToken.expires) > 5*time.Minute {
+		return azToken.value, nil
+	}
+	out, err := exec.Command("az", "account", "get-access-token", "--resource", azureDevOpsResource, "--output", "json").Output()
+	if err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("az account get-access-token: %s (run az login)", strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("az account get-access-token: %w (is the Azure CLI installed?)", err)
+	}
+	var resp struct {
+		AccessToken string `json:"accessToken"`
+		// ExpiresOn is local time; newer CLI versions also report ExpiresOnUnix.
+		ExpiresOn     string `json:"expiresOn"`
+		ExpiresOnUnix int64  `json:"expires_on"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return "", fmt.Errorf("az account get-access-token: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return "", errors.New("az account get-access-token returned no token")
+	}
+	expires := time.Now().Add(time.Hour)
+	if resp.ExpiresOnUnix > 0 {
+		expires = time.Unix(resp.ExpiresOnUnix, 0)
+	} else if t, err := time.ParseInLocation("2006-01-02 15:04:05.999999", resp.ExpiresOn, time.Local); err == nil {
+		expires = t
+	}
+	azToken.value, azToken.expires = resp.AccessToken, expires
+	return azToken.value, nil
+}
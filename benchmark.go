@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// benchmarkLevels are the concurrency levels tried by --benchmark.
+var benchmarkLevels = []int{1, 2, 4, 8, 16, 32}
+
+// maxBenchmarkRequests caps the status calls made by one --benchmark run across all
+// levels, so a large PR list does not turn into a load test against the server.
+const maxBenchmarkRequests = 600
+
+// benchmarkResult is the outcome of one concurrency level.
+type benchmarkResult struct {
+	Concurrency int
+	Calls       int
+	Duration    time.Duration
+	Throttled   int
+	Failed      int
+}
+
+// runBenchmark fetches check statuses for the same PRs at each level of
+// benchmarkLevels, with adaptive concurrency pinned to that level, and returns one
+// result per level.
+func runBenchmark(cfg config, prs []pullRequest) []benchmarkResult {
+	var sample []pullRequest
+	for _, pr := range prs {
+		if pr.Repository.ID != "" {
+			sample = append(sample, pr)
+		}
+	}
+	sample = sample[:min(len(sample), maxBenchmarkRequests/len(benchmarkLevels))]
+
+	results := make([]benchmarkResult, 0, len(benchmarkLevels))
+	for _, n := range benchmarkLevels {
+		lim := newAdaptiveLimiter(n, n, n)
+		res := benchmarkResult{Concurrency: n, Calls: len(sample)}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		start := time.Now()
+		for _, pr := range sample {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// count this call's own 429s; the process-wide count includes concurrent calls
+				var throttles atomic.Int64
+				err := lim.do(func(ctx context.Context) error {
+					_, err := getPRStatusOverall(azdo.CountThrottles(ctx, &throttles), cfg.forOrg(pr.Org), pr)
+					return err
+				})
+				if err != nil {
+					debugLog.Printf("benchmark: PR %d: %v", pr.PullRequestID, err)
+				}
+				mu.Lock()
+				res.Throttled += int(throttles.Load())
+				if err != nil {
+					res.Failed++
+				}
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		res.Duration = time.Since(start)
+		results = append(results, res)
+	}
+	return results
+}
+
+// fastestLevel returns the index of the quickest level without failed calls, or -1.
+func fastestLevel(results []benchmarkResult) int {
+	best := -1
+	for i, r := range results {
+		if r.Failed == 0 && (best < 0 || r.Duration < results[best].Duration) {
+			best = i
+		}
+	}
+	return best
+}
+
+// printBenchmark renders the --benchmark results and recommends a --concurrency.
+func printBenchmark(w io.Writer, cfg config, results []benchmarkResult) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(resolveTheme(cfg).Style)
+	t.AppendHeader(table.Row{"Concurrency", "Calls", "Duration", "429s", "Failed", ""})
+	best := fastestLevel(results)
+	for i, r := range results {
+		mark := ""
+		if i == best {
+			mark = "◀ fastest"
+		}
+		t.AppendRow(table.Row{r.Concurrency, r.Calls, r.Duration.Round(time.Millisecond), r.Throttled, r.Failed, mark})
+	}
+	t.Render()
+	if best >= 0 {
+		fmt.Fprintf(w, "Fastest: --concurrency %d\n", results[best].Concurrency)
+	}
+}
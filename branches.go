@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// staleBranch is a branch listed by "branches stale".
+type staleBranch struct {
+	Repo       string    `json:"repo"`
+	RepoID     string    `json:"repoId"`
+	Name       string    `json:"name"`
+	CommitID   string    `json:"commitId"`
+	LastCommit time.Time `json:"lastCommit"`
+	Author     string    `json:"author"`
+	Ahead      int       `json:"ahead"`
+	Behind     int       `json:"behind"`
+}
+
+// findStaleBranches lists the branches of repo whose last commit is older than cutoff,
+// leaving out the default branch and branches that are the source or target of an
+// active pull request. The oldest come first.
+func findStaleBranches(cfg config, repo repository, cutoff time.Time) ([]staleBranch, error) {
+	c := apiClient(cfg, 30*time.Second)
+	branches, err := c.BranchStats(context.Background(), cfg.Project, repo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing branches of %s: %w", repo.Name, err)
+	}
+	prs, err := c.ActivePullRequests(context.Background(), cfg.Project, repo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing pull requests of %s: %w", repo.Name, err)
+	}
+	inUse := map[string]bool{strings.ToLower(repo.DefaultBranch): true}
+	for _, pr := range prs {
+		inUse[strings.ToLower(pr.SourceRefName)] = true
+		inUse[strings.ToLower(pr.TargetRefName)] = true
+	}
+
+	var stale []staleBranch
+	for _, b := range branches {
+		last := b.Commit.Committer.Date.Time
+		if b.IsBaseVersion || inUse[strings.ToLower("refs/heads/"+b.Name)] || last.IsZero() || !last.Before(cutoff) {
+			continue
+		}
+		stale = append(stale, staleBranch{
+			Repo:       repo.Name,
+			RepoID:     repo.ID,
+			Name:       b.Name,
+			CommitID:   b.Commit.CommitID,
+			LastCommit: last,
+			Author:     b.Commit.Author.Name,
+			Ahead:      b.AheadCount,
+			Behind:     b.BehindCount,
+		})
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].LastCommit.Before(stale[j].LastCommit) })
+	return stale, nil
+}
+
+// printStaleBranches writes one row per stale branch.
+func printStaleBranches(w io.Writer, cfg config, olderThan string, branches []staleBranch) {
+	th := resolveTheme(cfg)
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(th.Style)
+	t.SetTitle(fmt.Sprintf("Branches without commits in %s and without active PRs", olderThan))
+	t.AppendHeader(table.Row{"Repo", "Branch", "Last commit", "Author", "Ahead", "Behind"})
+	for _, b := range branches {
+		t.AppendRow(table.Row{b.Repo, b.Name, relTime(cfg, b.LastCommit), b.Author, b.Ahead, b.Behind})
+	}
+	t.Render()
+}
+
+// deleteBranches deletes branches, one call per repository, and reports each branch.
+// It returns how many could not be deleted.
+func deleteBranches(cfg config, branches []staleBranch) int {
+	c := apiClient(cfg, 30*time.Second)
+	byRepo := map[string][]staleBranch{}
+	var order []string
+	for _, b := range branches {
+		if byRepo[b.RepoID] == nil {
+			order = append(order, b.RepoID)
+		}
+		byRepo[b.RepoID] = append(byRepo[b.RepoID], b)
+	}
+	failed := 0
+	for _, repoID := range order {
+		updates := make([]azdo.RefUpdate, 0, len(byRepo[repoID]))
+		for _, b := range byRepo[repoID] {
+			updates = append(updates, azdo.RefUpdate{Name: "refs/heads/" + b.Name, OldObjectID: b.CommitID, NewObjectID: azdo.ZeroObjectID})
+		}
+		repo := byRepo[repoID][0].Repo
+		results, err := c.UpdateRefs(context.Background(), cfg.Project, repoID, updates)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: deleting branches of %s: %v\n", repo, err)
+			failed += len(updates)
+			continue
+		}
+		for _, r := range results {
+			name := refShort(r.Name)
+			if r.Success {
+				fmt.Printf("Deleted %s/%s\n", repo, name)
+				continue
+			}
+			failed++
+			reason := r.UpdateStatus
+			if r.CustomMessage != "" {
+				reason += ": " + r.CustomMessage
+			}
+			fmt.Fprintf(os.Stderr, "Error: deleting %s/%s: %s\n", repo, name, reason)
+		}
+	}
+	return failed
+}
+
+// runBranchesStale runs "branches stale": it lists the branches of the --repo
+// repositories without commits for --older-than and without active pull requests,
+// and with --delete removes them after asking for confirmation.
+func runBranchesStale(args []string) {
+	olderThan := flag.String("older-than", "90d", "Only list branches whose last commit is older than this, e.g. 90d or 12w")
+	del := flag.Bool("delete", false, "Delete the listed branches")
+	yes := flag.Bool("yes", false, "Do not ask for confirmation before deleting")
+	cfg := getConfig(args)
+	if cfg.multiProject() {
+		failUsage("branches stale needs a single --project")
+	}
+	if len(cfg.Repos) == 0 {
+		failUsage("--repo is required")
+	}
+	age, err := parseAge(*olderThan)
+	if err != nil {
+		failUsage("--older-than: " + err.Error())
+	}
+	cutoff := time.Now().Add(-age)
+
+	var repos repoIndex
+	var stale []staleBranch
+	for _, name := range cfg.Repos {
+		repo, err := repos.lookup(cfg, name)
+		if err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		branches, err := findStaleBranches(cfg, repo, cutoff)
+		if err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		stale = append(stale, branches...)
+	}
+
+	out, closeOut := openOutput(cfg)
+	switch {
+	case cfg.Output == outputJSON:
+		if err := printJSON(out, orEmpty(stale)); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case len(stale) == 0:
+		fmt.Fprintf(out, "No branches without commits in %s and without active PRs.\n", *olderThan)
+	default:
+		printStaleBranches(out, cfg, *olderThan, stale)
+	}
+	closeOut()
+
+	if !*del || len(stale) == 0 {
+		return
+	}
+	if !*yes && !confirm(fmt.Sprintf("Delete these %d branches?", len(stale))) {
+		fmt.Println("Aborted.")
+		return
+	}
+	if failed := deleteBranches(cfg, stale); failed > 0 {
+		log.Fatalf("Error: %d of %d branches could not be deleted\n", failed, len(stale))
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// branchPolicy is a policy listed by "policies list".
+type branchPolicy struct {
+	Repo     string              `json:"repo"`
+	Branch   string              `json:"branch"`
+	ID       int                 `json:"id"`
+	Type     string              `json:"type"`
+	Enabled  bool                `json:"enabled"`
+	Required bool                `json:"required"`
+	Details  string              `json:"details"`
+	Settings azdo.PolicySettings `json:"settings"`
+}
+
+// policyNames resolves the pipelines and reviewers that policies refer to, for their
+// details. Failures only leave IDs unresolved.
+type policyNames struct {
+	pipelines map[int]string
+	reviewers map[string]string
+}
+
+func loadPolicyNames(cfg config, policies []azdo.PolicyConfiguration) policyNames {
+	n := policyNames{pipelines: map[int]string{}, reviewers: map[string]string{}}
+	var reviewerIDs []string
+	needPipelines := false
+	for _, p := range policies {
+		reviewerIDs = append(reviewerIDs, p.Settings.RequiredReviewerIDs...)
+		needPipelines = needPipelines || p.Settings.BuildDefinitionID != 0
+	}
+	if needPipelines {
+		defs, err := apiClient(cfg, 15*time.Second).BuildDefinitions(context.Background(), cfg.Project, "")
+		if err != nil {
+			debugLog.Printf("policies: pipeline names unavailable: %v", err)
+		}
+		for _, d := range defs {
+			n.pipelines[d.ID] = d.Name
+		}
+	}
+	if names, err := identityNames(cfg, reviewerIDs); err != nil {
+		debugLog.Printf("policies: reviewer names unavailable: %v", err)
+	} else {
+		n.reviewers = names
+	}
+	return n
+}
+
+// policyDetails summarizes the settings of p that matter for an audit, e.g.
+// "2 approvals, votes reset on push".
+func policyDetails(p azdo.PolicyConfiguration, names policyNames) string {
+	s := p.Settings
+	var parts []string
+	switch {
+	case p.HasType(azdo.PolicyMinimumReviewers):
+		parts = append(parts, fmt.Sprintf("%d approvals", s.MinimumApproverCount))
+		if s.CreatorVoteCounts {
+			parts = append(parts, "author's vote counts")
+		}
+		if s.ResetOnSourcePush {
+			parts = append(parts, "votes reset on push")
+		}
+	case p.HasType(azdo.PolicyBuild):
+		pipeline := names.pipelines[s.BuildDefinitionID]
+		if pipeline == "" {
+			pipeline = fmt.Sprintf("pipeline %d", s.BuildDefinitionID)
+		}
+		parts = append(parts, pipeline)
+		if s.ValidDuration > 0 {
+			parts = append(parts, "expires after "+(time.Duration(s.ValidDuration)*time.Minute).String())
+		}
+		if s.ManualQueueOnly {
+			parts = append(parts, "queued manually")
+		}
+	case p.HasType(azdo.PolicyRequiredReviewers):
+		reviewers := make([]string, 0, len(s.RequiredReviewerIDs))
+		for _, id := range s.RequiredReviewerIDs {
+			if name := names.reviewers[strings.ToLower(id)]; name != "" {
+				reviewers = append(reviewers, name)
+			} else {
+				reviewers = append(reviewers, id)
+			}
+		}
+		parts = append(parts, strings.Join(reviewers, ", "))
+		if s.MinimumApproverCount > 0 && s.MinimumApproverCount < len(reviewers) {
+			parts = append(parts, fmt.Sprintf("%d of them", s.MinimumApproverCount))
+		}
+		if len(s.FilenamePatterns) > 0 {
+			parts = append(parts, "for "+strings.Join(s.FilenamePatterns, " "))
+		}
+	case p.HasType(azdo.PolicyCommentResolution):
+		parts = append(parts, "all comments resolved")
+	case p.HasType(azdo.PolicyWorkItemLinking):
+		parts = append(parts, "a linked work item")
+	case p.HasType(azdo.PolicyMergeStrategy):
+		var allowed []string
+		for _, m := range []struct {
+			on   bool
+			name string
+		}{{s.AllowSquash, "squash"}, {s.AllowNoFastForward, "merge"}, {s.AllowRebase, "rebase"}, {s.AllowRebaseMerge, "rebase-merge"}} {
+			if m.on {
+				allowed = append(allowed, m.name)
+			}
+		}
+		parts = append(parts, "allowed: "+strings.Join(allowed, ", "))
+	}
+	if s.DisplayName != "" {
+		parts = append(parts, fmt.Sprintf("%q", s.DisplayName))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printBranchPolicies writes one row per policy.
+func printBranchPolicies(w io.Writer, cfg config, policies []branchPolicy) {
+	th := resolveTheme(cfg)
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(th.Style)
+	t.SetTitle("Branch policies")
+	t.AppendHeader(table.Row{"Repo", "Branch", "Policy", "Required", "Details"})
+	for _, p := range policies {
+		name, required := p.Type, "optional"
+		if !p.Enabled {
+			name += " (disabled)"
+		}
+		if p.Required {
+			required = "yes"
+		}
+		t.AppendRow(table.Row{p.Repo, p.Branch, name, required, truncate(p.Details, 80)})
+	}
+	t.Render()
+}
+
+// runPoliciesList runs "policies list": the branch policies protecting --branch of the
+// --repo repositories, for auditing protection settings.
+func runPoliciesList(args []string) {
+	branch := flag.String("branch", "", "Branch whose policies to list (default: each repository's default branch)")
+	cfg := getConfig(args)
+	if cfg.multiProject() {
+		failUsage("policies list needs a single --project")
+	}
+	if len(cfg.Repos) == 0 {
+		failUsage("--repo is required")
+	}
+
+	c := apiClient(cfg, 15*time.Second)
+	var repos repoIndex
+	var configs []azdo.PolicyConfiguration
+	var policies []branchPolicy
+	for _, name := range cfg.Repos {
+		repo, err := repos.lookup(cfg, name)
+		if err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		ref := repo.DefaultBranch
+		if *branch != "" {
+			ref = "refs/heads/" + strings.TrimPrefix(*branch, "refs/heads/")
+		}
+		if ref == "" {
+			log.Fatalf("Error: %s has no default branch; use --branch\n", repo.Name)
+		}
+		found, err := c.BranchPolicies(context.Background(), cfg.Project, repo.ID, ref)
+		if err != nil {
+			log.Fatalf("Error: listing the policies of %s: %v\n", repo.Name, err)
+		}
+		for _, p := range found {
+			if p.IsDeleted {
+				continue
+			}
+			configs = append(configs, p)
+			policies = append(policies, branchPolicy{
+				Repo:     repo.Name,
+				Branch:   refShort(ref),
+				ID:       p.ID,
+				Type:     p.Type.DisplayName,
+				Enabled:  p.IsEnabled,
+				Required: p.IsBlocking,
+				Settings: p.Settings,
+			})
+		}
+	}
+	names := loadPolicyNames(cfg, configs)
+	for i := range policies {
+		policies[i].Details = policyDetails(configs[i], names)
+	}
+
+	out, closeOut := openOutput(cfg)
+	defer closeOut()
+	switch {
+	case cfg.Output == outputJSON:
+		if err := printJSON(out, orEmpty(policies)); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case len(policies) == 0:
+		fmt.Fprintln(out, "No branch policies apply.")
+	default:
+		printBranchPolicies(out, cfg, policies)
+	}
+}
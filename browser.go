@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// maxOpenWithoutConfirm is how many browser tabs may be opened before asking first.
+const maxOpenWithoutConfirm = 10
+
+// openBrowser launches the platform's default handler for u without waiting for it.
+func openBrowser(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	case "darwin":
+		cmd = exec.Command("open", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	return cmd.Start()
+}
+
+// openFailing opens the web page of every PR whose checks failed,
+// asking for confirmation when that would open more than maxOpenWithoutConfirm tabs.
+func openFailing(recs []prRecord) {
+	var urls []string
+	for _, r := range recs {
+		if r.Checks == "Failed" && r.Links.Web.Href != "" {
+			urls = append(urls, r.Links.Web.Href)
+		}
+	}
+	if len(urls) == 0 {
+		fmt.Println(msg(msgNoFailingToOpen))
+		return
+	}
+	if len(urls) > maxOpenWithoutConfirm && !confirm(msgN(msgConfirmOpen, len(urls))) {
+		return
+	}
+	for _, u := range urls {
+		if err := openBrowser(u); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not open browser:", err)
+			return
+		}
+	}
+}
+
+// confirm asks a yes/no question on stdin; anything but y/yes counts as no.
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
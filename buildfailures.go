@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+)
+
+// defaultLogLines is how many lines of a failed task's log --log-lines shows by default.
+const defaultLogLines = 20
+
+// buildFailure is a failed task of the build behind a check.
+type buildFailure struct {
+	Task   string   `json:"task"`
+	Issues []string `json:"issues,omitempty"`
+	// Log holds the last lines of the task's log.
+	Log []string `json:"log,omitempty"`
+}
+
+// checkBuildID returns the ID of the build a status links to, or 0.
+func checkBuildID(targetURL string) int {
+	u, err := url.Parse(targetURL)
+	if err != nil || !strings.Contains(u.Path, "/_build/results") {
+		return 0
+	}
+	id, _ := strconv.Atoi(u.Query().Get("buildId"))
+	return id
+}
+
+// fetchBuildFailures lists the failed tasks of build id with their error issues and the
+// last lines of their logs (none when lines is 0).
+func fetchBuildFailures(cfg config, project string, id, lines int) ([]buildFailure, error) {
+	c := apiClient(cfg, 30*time.Second)
+	records, err := c.Timeline(context.Background(), project, id)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]azdo.TimelineRecord, len(records))
+	for _, r := range records {
+		byID[r.ID] = r
+	}
+	var failed []azdo.TimelineRecord
+	for _, r := range records {
+		if r.Type == "Task" && r.Result == "failed" {
+			failed = append(failed, r)
+		}
+	}
+	sortByStart(failed)
+	var failures []buildFailure
+	for _, r := range failed {
+		f := buildFailure{Task: taskPath(byID, r)}
+		for _, is := range r.Issues {
+			if is.Type == "error" {
+				f.Issues = append(f.Issues, is.Message)
+			}
+		}
+		if r.Log != nil && lines > 0 {
+			all, err := c.BuildLog(context.Background(), project, id, r.Log.ID, 0)
+			if err != nil {
+				return nil, fmt.Errorf("log of %s: %w", r.Name, err)
+			}
+			f.Log = all[max(0, len(all)-lines):]
+		}
+		failures = append(failures, f)
+	}
+	return failures, nil
+}
+
+// addBuildFailures loads the failed tasks of the builds behind pr's failed checks into
+// checks. Each build is loaded once even if several checks refer to it.
+func addBuildFailures(cfg config, pr pullRequest, checks []prCheck, lines int) error {
+	loaded := map[int][]buildFailure{}
+	var errs []error
+	for i, c := range checks {
+		if c.BuildID == 0 || checkWords[strings.ToLower(c.State)] != "Failed" {
+			continue
+		}
+		failures, ok := loaded[c.BuildID]
+		if !ok {
+			var err error
+			if failures, err = fetchBuildFailures(cfg, prProject(cfg, pr), c.BuildID, lines); err != nil {
+				errs = append(errs, fmt.Errorf("build %d: %w", c.BuildID, err))
+			}
+			loaded[c.BuildID] = failures
+		}
+		checks[i].Failures = failures
+	}
+	return errors.Join(errs...)
+}
+
+// printBuildFailures writes the failed tasks of a check, indented by indent.
+func printBuildFailures(w io.Writer, th theme, indent string, failures []buildFailure) {
+	for _, f := range failures {
+		fmt.Fprintf(w, "%s%s %s\n", indent, th.checkState("failed"), f.Task)
+		for _, is := range f.Issues {
+			fmt.Fprintf(w, "%s  error: %s\n", indent, is)
+		}
+		for _, line := range f.Log {
+			fmt.Fprintf(w, "%s  | %s\n", indent, line)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+)
+
+// taskPath names record by its stage, job and task, e.g. "Build › Linux › Run tests".
+func taskPath(byID map[string]azdo.TimelineRecord, r azdo.TimelineRecord) string {
+	var names []string
+	for ; ; r = byID[r.ParentID] {
+		switch r.Type {
+		case "Stage", "Job", "Task":
+			names = append([]string{r.Name}, names...)
+		}
+		if r.ParentID == "" || byID[r.ParentID].ID == "" {
+			return strings.Join(names, " › ")
+		}
+	}
+}
+
+// logTasks returns the tasks of a timeline that have a log, in the order they ran.
+func logTasks(records []azdo.TimelineRecord) []azdo.TimelineRecord {
+	var tasks []azdo.TimelineRecord
+	for _, r := range records {
+		if r.Type == "Task" && r.Log != nil {
+			tasks = append(tasks, r)
+		}
+	}
+	sortByStart(tasks)
+	return tasks
+}
+
+// sortByStart orders timeline records by when they started, records that did not start
+// yet last.
+func sortByStart(records []azdo.TimelineRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i].StartTime, records[j].StartTime
+		if a.IsZero() != b.IsZero() {
+			return !a.IsZero()
+		}
+		if !a.Equal(b.Time) {
+			return a.Before(b.Time)
+		}
+		return records[i].Order < records[j].Order
+	})
+}
+
+// logTail prints the log lines of a build's tasks that were not printed yet. printed
+// counts the lines printed per task; tasks that completed are not fetched again.
+type logTail struct {
+	cfg     config
+	build   int
+	printed map[string]int
+	done    map[string]bool
+}
+
+// poll prints what was added to the logs since the previous poll.
+func (lt *logTail) poll(w io.Writer) error {
+	c := apiClient(lt.cfg, 30*time.Second)
+	records, err := c.Timeline(context.Background(), lt.cfg.Project, lt.build)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]azdo.TimelineRecord, len(records))
+	for _, r := range records {
+		byID[r.ID] = r
+	}
+	for _, t := range logTasks(records) {
+		if lt.done[t.ID] {
+			continue
+		}
+		n := lt.printed[t.ID]
+		lines, err := c.BuildLog(context.Background(), lt.cfg.Project, lt.build, t.Log.ID, n+1)
+		if err != nil {
+			return fmt.Errorf("log of %s: %w", t.Name, err)
+		}
+		if n == 0 && len(lines) > 0 {
+			fmt.Fprintf(w, "==> %s\n", taskPath(byID, t))
+		}
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+		lt.printed[t.ID] = n + len(lines)
+		lt.done[t.ID] = t.State == "completed"
+	}
+	return nil
+}
+
+// runBuildsLogs runs "builds logs <id>": it prints the logs of a build's tasks and, with
+// --follow, keeps printing new lines until the build finishes.
+func runBuildsLogs(args []string) {
+	follow := flag.Bool("follow", false, "Keep printing new log lines until the build finishes")
+	cfg := getConfig(args)
+	if len(cfg.Args) != 1 {
+		failUsage("usage: lazydevops builds logs <build-id> [--follow]")
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(cfg.Args[0], "#"))
+	if err != nil || id <= 0 {
+		failUsage(fmt.Sprintf("invalid build ID %q", cfg.Args[0]))
+	}
+	if cfg.multiProject() {
+		failUsage("builds logs needs a single --project")
+	}
+
+	c := apiClient(cfg, 15*time.Second)
+	lt := &logTail{cfg: cfg, build: id, printed: map[string]int{}, done: map[string]bool{}}
+	for {
+		b, err := c.Build(context.Background(), cfg.Project, id)
+		if err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		if err := lt.poll(os.Stdout); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		if !*follow {
+			return
+		}
+		if b.Status == "completed" {
+			// the poll above already saw the final logs
+			fmt.Printf("==> Build %s %s\n", b.BuildNumber, resolveTheme(cfg).checkState(buildState(b)))
+			if b.Result != "succeeded" {
+				os.Exit(exitRunFailed)
+			}
+			return
+		}
+		time.Sleep(followInterval)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLogTailPollFetchesOnlyNewLines(t *testing.T) {
+	lines := []string{"one", "two"}
+	var startLines []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/timeline") {
+			json.NewEncoder(w).Encode(map[string]any{"records": []map[string]any{
+				{"id": "t1", "type": "Task", "name": "Build", "state": "inProgress", "log": map[string]int{"id": 3}},
+			}})
+			return
+		}
+		start := r.URL.Query().Get("startLine")
+		startLines = append(startLines, start)
+		from, _ := strconv.Atoi(start)
+		json.NewEncoder(w).Encode(map[string]any{"value": lines[max(0, from-1):]})
+	}))
+	defer srv.Close()
+	lt := &logTail{cfg: config{BaseURL: srv.URL, Project: "proj"}, build: 7, printed: map[string]int{}, done: map[string]bool{}}
+
+	var sb strings.Builder
+	if err := lt.poll(&sb); err != nil {
+		t.Fatal(err)
+	}
+	lines = append(lines, "three")
+	if err := lt.poll(&sb); err != nil {
+		t.Fatal(err)
+	}
+	if want := "==> Build\none\ntwo\nthree\n"; sb.String() != want {
+		t.Errorf("output = %q, want %q", sb.String(), want)
+	}
+	if len(startLines) != 2 || startLines[0] != "1" || startLines[1] != "3" {
+		t.Errorf("startLine = %q, want 1 then 3", startLines)
+	}
+}
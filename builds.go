@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// build is a pipeline run as returned by the Builds API.
+type build = azdo.Build
+
+// findPipeline resolves a pipeline given by ID or name (case-insensitive; the folder
+// may be left out).
+func findPipeline(cfg config, pipeline string) (azdo.BuildDefinition, error) {
+	c := apiClient(cfg, 15*time.Second)
+	if id, err := strconv.Atoi(pipeline); err == nil {
+		return azdo.BuildDefinition{ID: id, Name: pipeline}, nil
+	}
+	defs, err := c.BuildDefinitions(context.Background(), cfg.Project, pipeline)
+	if err != nil {
+		return azdo.BuildDefinition{}, fmt.Errorf("looking up pipeline %q: %w", pipeline, err)
+	}
+	switch len(defs) {
+	case 0:
+		return azdo.BuildDefinition{}, fmt.Errorf("no pipeline named %q in project %s", pipeline, cfg.Project)
+	case 1:
+		return defs[0], nil
+	}
+	names := make([]string, 0, len(defs))
+	for _, d := range defs {
+		name := d.Name
+		if folder := strings.Trim(d.Path, `\`); folder != "" {
+			name = folder + `\` + name
+		}
+		names = append(names, fmt.Sprintf("%s (%d)", name, d.ID))
+	}
+	return azdo.BuildDefinition{}, fmt.Errorf("%q matches several pipelines, use the ID: %s", pipeline, strings.Join(names, ", "))
+}
+
+// buildState is the word shown for b: its result once completed, else its status.
+func buildState(b build) string {
+	if b.Status == "completed" && b.Result != "" {
+		return b.Result
+	}
+	return b.Status
+}
+
+// buildDuration is how long b ran, or has been running so far.
+func buildDuration(b build) time.Duration {
+	switch {
+	case b.StartTime.IsZero():
+		return 0
+	case b.FinishTime.IsZero():
+		return time.Since(b.StartTime.Time)
+	}
+	return b.FinishTime.Sub(b.StartTime.Time)
+}
+
+// printBuilds writes one row per build.
+func printBuilds(w io.Writer, cfg config, builds []build) {
+	th := resolveTheme(cfg)
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(th.Style)
+	t.SetTitle(fmt.Sprintf("Builds in %s", cfg.Project))
+	t.AppendHeader(table.Row{"ID", "Pipeline", "Number", "Branch", "State", "Requested for", "Started", "Duration", "URL"})
+	for _, b := range builds {
+		started, duration := "", ""
+		if !b.StartTime.IsZero() {
+			started = relTime(cfg, b.StartTime.Time)
+			duration = buildDuration(b).Round(time.Second).String()
+		}
+		t.AppendRow(table.Row{b.ID, b.Definition.Name, b.BuildNumber, refShort(b.SourceBranch), th.checkState(buildState(b)),
+			b.RequestedFor.DisplayName, started, duration, b.Links.Web.Href})
+	}
+	t.Render()
+}
+
+// runBuildsList runs "builds list": the latest builds of the project, optionally of one
+// pipeline and branch.
+func runBuildsList(args []string) {
+	pipeline := flag.String("pipeline", "", "Only list builds of this pipeline (name or ID)")
+	branch := flag.String("branch", "", "Only list builds of this branch (name or full ref)")
+	cfg := getConfig(args)
+	if cfg.multiProject() {
+		failUsage("builds list needs a single --project")
+	}
+
+	q := url.Values{}
+	if cfg.Top > 0 {
+		q.Set("$top", strconv.Itoa(cfg.Top))
+	}
+	if *pipeline != "" {
+		def, err := findPipeline(cfg, *pipeline)
+		if err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		q.Set("definitions", strconv.Itoa(def.ID))
+	}
+	if *branch != "" {
+		q.Set("branchName", branchRef(*branch))
+	}
+	builds, err := apiClient(cfg, 0).Builds(context.Background(), cfg.Project, q)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+
+	out, closeOut := openOutput(cfg)
+	defer closeOut()
+	switch {
+	case cfg.Output == outputJSON:
+		if err := printJSON(out, orEmpty(builds)); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case len(builds) == 0:
+		fmt.Fprintln(out, "No builds found.")
+	default:
+		printBuilds(out, cfg, builds)
+	}
+}
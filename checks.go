@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// prCheck is one status or policy evaluation on a pull request, as listed by "pr checks".
+type prCheck struct {
+	Kind        string    `json:"kind"` // "status" or "policy"
+	Name        string    `json:"name"`
+	State       string    `json:"state"`
+	Required    bool      `json:"required"`
+	Description string    `json:"description,omitempty"`
+	TargetURL   string    `json:"targetUrl,omitempty"`
+	Updated     time.Time `json:"updated"`
+	// BuildID is the build behind the check, 0 when it is not a build.
+	BuildID int `json:"buildId,omitempty"`
+	// Failures are the failed tasks of that build, loaded by addBuildFailures.
+	Failures []buildFailure `json:"failures,omitempty"`
+}
+
+// checkWords maps status, policy evaluation and build states to the overall check words,
+// so single checks and builds are colored like the Checks column.
+var checkWords = map[string]string{
+	"succeeded":          "Passed",
+	"approved":           "Passed",
+	"failed":             "Failed",
+	"error":              "Failed",
+	"rejected":           "Failed",
+	"broken":             "Failed",
+	"pending":            "In Progress",
+	"queued":             "In Progress",
+	"running":            "In Progress",
+	"notapplicable":      "N/A",
+	"notset":             "N/A",
+	"partiallysucceeded": "Failed",
+	"canceled":           "N/A",
+	"inprogress":         "In Progress",
+	"notstarted":         "In Progress",
+	"cancelling":         "In Progress",
+}
+
+// checkState renders the state of a single check in the color of its overall word.
+func (th theme) checkState(state string) string {
+	if col, ok := th.Checks[checkWords[strings.ToLower(state)]]; ok {
+		return col.Sprint(state)
+	}
+	return state
+}
+
+// fetchPRStatuses lists the statuses posted to pr, keeping only the latest per context
+// since services post a new status for every iteration.
+func fetchPRStatuses(cfg config, pr pullRequest) ([]prStatus, error) {
+	all, err := apiClient(cfg, 15*time.Second).PullRequestStatuses(context.Background(), prProject(cfg, pr), pr)
+	if err != nil {
+		return nil, err
+	}
+	latest := map[string]prStatus{}
+	var order []string
+	for _, s := range all {
+		key := s.Context.Genre + "/" + s.Context.Name
+		prev, seen := latest[key]
+		if !seen {
+			order = append(order, key)
+		}
+		if !seen || statusTime(s).After(statusTime(prev)) {
+			latest[key] = s
+		}
+	}
+	statuses := make([]prStatus, 0, len(order))
+	for _, key := range order {
+		statuses = append(statuses, latest[key])
+	}
+	return statuses, nil
+}
+
+// statusTime is when s was last updated, or created if it never was.
+func statusTime(s prStatus) time.Time {
+	if s.UpdatedDate.IsZero() {
+		return s.CreationDate.Time
+	}
+	return s.UpdatedDate.Time
+}
+
+// prChecks gathers the statuses and enabled policy evaluations of pr, required ones first.
+func prChecks(cfg config, pr pullRequest) ([]prCheck, error) {
+	statuses, err := fetchPRStatuses(cfg, pr)
+	if err != nil {
+		return nil, fmt.Errorf("loading statuses: %w", err)
+	}
+	evals, err := fetchPolicyEvaluations(context.Background(), cfg, pr)
+	if err != nil {
+		return nil, fmt.Errorf("loading policy evaluations: %w", err)
+	}
+
+	var checks []prCheck
+	for _, s := range statuses {
+		name := s.Context.Name
+		if s.Context.Genre != "" {
+			name = s.Context.Genre + "/" + name
+		}
+		checks = append(checks, prCheck{
+			Kind:        "status",
+			Name:        name,
+			State:       s.State,
+			Description: s.Description,
+			TargetURL:   s.TargetURL,
+			Updated:     statusTime(s),
+			BuildID:     checkBuildID(s.TargetURL),
+		})
+	}
+	for _, ev := range evals {
+		c := ev.Configuration
+		if !c.IsEnabled {
+			continue
+		}
+		name := c.Type.DisplayName
+		if c.Settings.DisplayName != "" {
+			name += ": " + c.Settings.DisplayName
+		}
+		check := prCheck{
+			Kind:     "policy",
+			Name:     name,
+			State:    ev.Status,
+			Required: c.IsBlocking,
+			Updated:  ev.CompletedDate.Time,
+		}
+		if check.Updated.IsZero() {
+			check.Updated = ev.StartedDate.Time
+		}
+		if ev.Context.BuildID != 0 {
+			check.BuildID = ev.Context.BuildID
+			check.TargetURL = apiClient(cfg, 0).WebURL(prProject(cfg, pr), fmt.Sprintf("_build/results?buildId=%d", ev.Context.BuildID))
+		}
+		checks = append(checks, check)
+	}
+	sort.SliceStable(checks, func(i, j int) bool { return checks[i].Required && !checks[j].Required })
+	return checks, nil
+}
+
+// printChecks writes one row per check of PR id.
+func printChecks(w io.Writer, cfg config, id int, checks []prCheck) {
+	th := resolveTheme(cfg)
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(th.Style)
+	t.SetTitle(fmt.Sprintf("Checks of PR #%d", id))
+	t.AppendHeader(table.Row{"Kind", "Name", "State", "Required", "Description", "Updated", "URL"})
+	for _, c := range checks {
+		required := ""
+		if c.Required {
+			required = "yes"
+		}
+		t.AppendRow(table.Row{c.Kind, c.Name, th.checkState(c.State), required, truncate(c.Description, 60), relTime(cfg, c.Updated), c.TargetURL})
+	}
+	t.Render()
+	for _, c := range checks {
+		if len(c.Failures) > 0 {
+			fmt.Fprintf(w, "\nFailed in build %d (%s):\n", c.BuildID, c.Name)
+			printBuildFailures(w, th, "  ", c.Failures)
+		}
+	}
+}
+
+// runPRChecks runs "pr checks <id>": it lists every status and branch policy evaluation
+// behind the PR's Checks cell, and the failed tasks of failed builds.
+func runPRChecks(args []string) {
+	logLines := flag.Int("log-lines", defaultLogLines, "Lines of each failed build task's log to show (0 for none)")
+	cfg := getConfig(args)
+	id := prArg(cfg, "checks")
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	checks, err := prChecks(cfg, pr)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	if err := addBuildFailures(cfg, pr, checks, *logLines); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: loading build failures:", err)
+	}
+
+	out, closeOut := openOutput(cfg)
+	defer closeOut()
+	switch {
+	case cfg.Output == outputJSON:
+		if err := printJSON(out, orEmpty(checks)); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case len(checks) == 0:
+		fmt.Fprintf(out, "PR #%d has no statuses or policy evaluations.\n", id)
+	default:
+		printChecks(out, cfg, id, checks)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// tableColumns maps the --columns names to the table headers, in the order "lazydevops
+// -h" lists them.
+var tableColumns = []struct{ Name, Header string }{
+	{"pr", "PR"},
+	{"title", "Title"},
+	{"description", "Description"},
+	{"author", "Author"},
+	{"role", "Role"},
+	{"org", "Org"},
+	{"project", "Project"},
+	{"repo", "Repo"},
+	{"branches", "Source->Target"},
+	{"votes", "Votes"},
+	{"gap", "Gap"},
+	{"wis", "WIs"},
+	{"comments", "Comments"},
+	{"labels", "Labels"},
+	{"checks", "Checks"},
+	{"created", "Created"},
+	{"updated", "Updated"},
+	{"url", "URL"},
+}
+
+// wideColumns are added to the default columns by --wide.
+var wideColumns = []string{"description", "comments", "labels"}
+
+// columnNames lists the valid --columns names.
+func columnNames() []string {
+	names := make([]string, len(tableColumns))
+	for i, c := range tableColumns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// columnHeader returns the table header of column name.
+func columnHeader(name string) string {
+	for _, c := range tableColumns {
+		if c.Name == name {
+			return c.Header
+		}
+	}
+	return name
+}
+
+// parseColumns validates a --columns list, lowercasing the names.
+func parseColumns(list []string) ([]string, error) {
+	cols := make([]string, 0, len(list))
+	for _, c := range list {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if !slices.Contains(columnNames(), c) {
+			return nil, fmt.Errorf("unknown column %q (use %s)", c, strings.Join(columnNames(), ", "))
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// tableColumnNames returns the columns of the PR table: --columns when given,
+// otherwise the default columns for the other flags, plus the --wide ones.
+func tableColumnNames(cfg config) []string {
+	if len(cfg.Columns) > 0 {
+		return cfg.Columns
+	}
+	var cols []string
+	for _, c := range columnNames() {
+		switch c {
+		case "role":
+			if !cfg.MyWork {
+				continue
+			}
+		case "org":
+			if !cfg.multiOrg() {
+				continue
+			}
+		case "project":
+			if !cfg.multiProject() {
+				continue
+			}
+		case "gap":
+			if !cfg.ShowGap {
+				continue
+			}
+		case "wis":
+			if !cfg.ShowWorkItems {
+				continue
+			}
+		case "updated":
+			continue
+		case "description", "comments", "labels":
+			if !cfg.Wide {
+				continue
+			}
+		}
+		cols = append(cols, c)
+	}
+	return cols
+}
+
+// formatComments renders the Comments cell, e.g. "2 active / 5".
+func formatComments(c *threadCounts) string {
+	if c == nil {
+		return "?"
+	}
+	if c.Active == 0 {
+		return fmt.Sprint(c.Active + c.Resolved)
+	}
+	return fmt.Sprintf("%d active / %d", c.Active, c.Active+c.Resolved)
+}
+
+// formatLabels renders the active labels (tags) of a PR.
+func formatLabels(pr pullRequest) string {
+	var names []string
+	for _, l := range pr.Labels {
+		if l.Active {
+			names = append(names, l.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
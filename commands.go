@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// command is a node of the CLI command tree: a group such as "prs" with subcommands,
+// or a leaf such as "prs list" that runs with the arguments following its name.
+type command struct {
+	Name    string
+	Aliases []string
+	Summary string
+	Subs    []*command
+	Run     func(args []string)
+}
+
+// commands is the command tree. Running the binary without a command (or with only
+// flags) is the same as "prs list".
+var commands = []*command{
+	{Name: "prs", Aliases: []string{"pr"}, Summary: "Pull requests", Subs: []*command{
+		{Name: "list", Aliases: []string{"ls"}, Summary: "List active pull requests with their checks and votes", Run: runPRList},
+		{Name: "approve", Aliases: []string{"vote"}, Summary: "Approve a pull request, or cast another vote with --vote", Run: runPRApprove},
+		{Name: "show", Aliases: []string{"view"}, Summary: "Show the details of a pull request", Run: runPRShow},
+		{Name: "open", Aliases: []string{"browse"}, Summary: "Open a pull request in the browser", Run: runPROpen},
+		{Name: "checks", Summary: "List the statuses and policy evaluations of a pull request", Run: runPRChecks},
+		{Name: "threads", Summary: "List the comment threads of a pull request", Run: runPRThreads},
+		{Name: "comment", Summary: "Reply to a comment thread with --thread, or start a new one", Run: runPRComment},
+		{Name: "resolve", Summary: "Resolve a comment thread, or reopen it with --reopen", Run: runPRResolve},
+		{Name: "reviewers", Summary: "Reviewers of a pull request", Subs: []*command{
+			{Name: "add", Summary: "Add reviewers to a pull request, with --required for required ones", Run: runPRReviewersAdd},
+			{Name: "remove", Aliases: []string{"rm"}, Summary: "Remove reviewers from a pull request", Run: runPRReviewersRemove},
+		}},
+		{Name: "link", Summary: "Link work items to a pull request with --work-item", Run: runPRLink},
+		{Name: "create", Summary: "Open a pull request from the checked-out branch", Run: runPRCreate},
+		{Name: "complete", Aliases: []string{"merge"}, Summary: "Merge a pull request with --strategy", Run: runPRComplete},
+		{Name: "autocomplete", Aliases: []string{"auto-complete"}, Summary: "Set auto-complete on a pull request, or cancel it with --off", Run: runPRAutocomplete},
+		{Name: "abandon", Summary: "Abandon an active pull request", Run: setPRStatus("abandon", "active", "abandoned")},
+		{Name: "reactivate", Summary: "Reactivate an abandoned pull request", Run: setPRStatus("reactivate", "abandoned", "active")},
+	}},
+	{Name: "repos", Aliases: []string{"repo"}, Summary: "Repositories", Subs: []*command{
+		{Name: "list", Aliases: []string{"ls"}, Summary: "List the project's repositories with their size and last push", Run: runReposList},
+	}},
+	{Name: "branches", Aliases: []string{"branch"}, Summary: "Branches", Subs: []*command{
+		{Name: "stale", Summary: "List branches without recent commits or active PRs, and with --delete remove them", Run: runBranchesStale},
+	}},
+	{Name: "policies", Aliases: []string{"policy"}, Summary: "Branch policies", Subs: []*command{
+		{Name: "list", Aliases: []string{"ls"}, Summary: "List the branch policies of a --repo and --branch", Run: runPoliciesList},
+	}},
+	{Name: "builds", Aliases: []string{"build"}, Summary: "Builds and pipeline runs", Subs: []*command{
+		{Name: "list", Aliases: []string{"ls"}, Summary: "List the latest builds, optionally of one --pipeline and --branch", Run: runBuildsList},
+		{Name: "logs", Summary: "Print the logs of a build, with --follow until it finishes", Run: runBuildsLogs},
+	}},
+	{Name: "pipeline", Aliases: []string{"pipelines"}, Summary: "Pipelines", Subs: []*command{
+		{Name: "run", Summary: "Queue a pipeline run, and with --follow wait for it", Run: runPipelineRun},
+	}},
+	{Name: "approvals", Aliases: []string{"approval"}, Summary: "Pipeline approvals", Subs: []*command{
+		{Name: "list", Aliases: []string{"ls"}, Summary: "List pending pipeline approvals assigned to you", Run: runApprovalsList},
+		{Name: "approve", Summary: "Approve a pending pipeline approval", Run: decideApproval("approve", "approved")},
+		{Name: "reject", Summary: "Reject a pending pipeline approval", Run: decideApproval("reject", "rejected")},
+	}},
+	{Name: "wit", Aliases: []string{"workitems"}, Summary: "Work items", Subs: []*command{
+		{Name: "list", Aliases: []string{"ls"}, Summary: "List your open work items, or those of --assigned-to", Run: runWitList},
+		{Name: "query", Summary: "Run a WIQL query given with --wiql, or a saved query with --saved", Run: runWitQuery},
+	}},
+	{Name: "digest", Summary: "Post a summary of the open PRs to a Slack or Teams webhook", Run: runDigest},
+	{Name: "serve", Summary: "Serve an auto-refreshing PR dashboard and JSON API over HTTP", Run: runServe},
+	{Name: "auth", Summary: "Credentials", Subs: []*command{
+		{Name: "login", Summary: "Store a PAT in the OS keyring", Run: runAuthLogin},
+		{Name: "logout", Summary: "Remove a stored PAT from the OS keyring", Run: runAuthLogout},
+	}},
+	{Name: "config", Summary: "Config file", Subs: []*command{
+		{Name: "init", Summary: "Write a commented config file to fill in", Run: runConfigInit},
+	}},
+}
+
+func (c *command) matches(name string) bool {
+	return c.Name == name || slices.Contains(c.Aliases, name)
+}
+
+func findCommand(cmds []*command, name string) *command {
+	for _, c := range cmds {
+		if c.matches(name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// runCommand resolves args to a leaf command and runs it.
+func runCommand(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		runPRList(args)
+		return
+	}
+	if args[0] == "help" {
+		printCommands(os.Stdout)
+		return
+	}
+	var path []string
+	cmds := commands
+	for {
+		c := findCommand(cmds, args[0])
+		if c == nil {
+			if len(path) == 0 {
+				failUsage(fmt.Sprintf("unknown command %q (see lazydevops help)", args[0]))
+			}
+			failUsage(fmt.Sprintf("unknown command %q for %q (see lazydevops help)", args[0], strings.Join(path, " ")))
+		}
+		path = append(path, c.Name)
+		args = args[1:]
+		if c.Run != nil {
+			c.Run(args)
+			return
+		}
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+			failUsage(fmt.Sprintf("%q needs a subcommand (see lazydevops help)", strings.Join(path, " ")))
+		}
+		cmds = c.Subs
+	}
+}
+
+// printCommands lists the command tree with one line per leaf command.
+func printCommands(w io.Writer) {
+	fmt.Fprintln(w, "Usage: lazydevops <command> [flags]")
+	fmt.Fprintln(w, "\nCommands:")
+	var list func(prefix string, cmds []*command)
+	list = func(prefix string, cmds []*command) {
+		for _, c := range cmds {
+			if c.Run == nil {
+				list(prefix+c.Name+" ", c.Subs)
+				continue
+			}
+			fmt.Fprintf(w, "  %-24s %s\n", prefix+c.Name, c.Summary)
+		}
+	}
+	list("", commands)
+	fmt.Fprintln(w, "\nWithout a command, lazydevops runs \"prs list\". Run a command with -h for its flags.")
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the optional config file. Flags and environment variables take precedence
+// over its values.
+type fileConfig struct {
+	Org string `yaml:"org,omitempty"`
+	// BaseURL is the Azure DevOps Server collection URL, like --base-url.
+	BaseURL string `yaml:"base-url,omitempty"`
+	Project string `yaml:"project,omitempty"`
+	// Pat is used when LAZY_DEV_OPS_PAT is not set. The file is written with owner-only permissions.
+	Pat string `yaml:"pat,omitempty"`
+	// Presets are named sets of flags (without dashes) applied with --preset, e.g.
+	//   presets:
+	//     triage: {my-work: true, sort: "checks,created"}
+	Presets map[string]map[string]any `yaml:"presets,omitempty"`
+	// APIVersion replaces the built-in default of --api-version.
+	APIVersion string `yaml:"api-version,omitempty"`
+	// Top and Output replace the built-in defaults of --top and --output.
+	Top    int    `yaml:"top,omitempty"`
+	Output string `yaml:"output,omitempty"`
+	// Orgs holds per-organization overrides keyed by organization name, e.g.
+	//   orgs:
+	//     legacy-tenant: {api-version: "6.0"}
+	Orgs map[string]orgConfig `yaml:"orgs,omitempty"`
+	// PatEnv names an environment variable to read the PAT from instead of LAZY_DEV_OPS_PAT.
+	PatEnv string `yaml:"pat-env,omitempty"`
+	// Profiles are named connection settings selected with --profile, e.g.
+	//   profiles:
+	//     client: {org: contoso, project: Web, pat-env: CONTOSO_PAT}
+	Profiles map[string]profileConfig `yaml:"profiles,omitempty"`
+}
+
+// profileConfig holds the settings of one profile; empty values fall back to the
+// top-level ones, except that a profile naming a PAT or PAT variable never falls back
+// to the top-level PAT.
+type profileConfig struct {
+	Org        string `yaml:"org,omitempty"`
+	BaseURL    string `yaml:"base-url,omitempty"`
+	Project    string `yaml:"project,omitempty"`
+	Pat        string `yaml:"pat,omitempty"`
+	PatEnv     string `yaml:"pat-env,omitempty"`
+	APIVersion string `yaml:"api-version,omitempty"`
+}
+
+// withProfile returns fc with the named profile's settings laid over the top-level ones.
+func (fc fileConfig) withProfile(name string) (fileConfig, error) {
+	p, ok := fc.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(fc.Profiles))
+		for n := range fc.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fc, fmt.Errorf("unknown profile %q: the config file defines no profiles", name)
+		}
+		return fc, fmt.Errorf("unknown profile %q (defined: %s)", name, strings.Join(names, ", "))
+	}
+	if p.Org != "" {
+		fc.Org = p.Org
+	}
+	if p.BaseURL != "" {
+		fc.BaseURL = p.BaseURL
+	}
+	if p.Project != "" {
+		fc.Project = p.Project
+	}
+	if p.Pat != "" || p.PatEnv != "" {
+		fc.Pat, fc.PatEnv = p.Pat, p.PatEnv
+	}
+	if p.APIVersion != "" {
+		fc.APIVersion = p.APIVersion
+	}
+	return fc, nil
+}
+
+// orgConfig holds settings that apply to a single organization.
+type orgConfig struct {
+	APIVersion string `yaml:"api-version,omitempty"`
+}
+
+// apiVersionFor returns the API version the file configures for org, and which part of
+// the file it came from: the org's own entry wins over the top-level api-version.
+// It returns "" when the file sets neither.
+func (fc fileConfig) apiVersionFor(org string) (version, source string) {
+	for name, oc := range fc.Orgs {
+		if strings.EqualFold(name, org) && oc.APIVersion != "" {
+			return oc.APIVersion, "file orgs." + name
+		}
+	}
+	if fc.APIVersion != "" {
+		return fc.APIVersion, sourceFile
+	}
+	return "", ""
+}
+
+// applyPreset sets the flags of the named preset on set, skipping flags given explicitly
+// on the command line so those override the preset. It returns the names of the flags
+// it set.
+func applyPreset(set *flag.FlagSet, fc fileConfig, name string) ([]string, error) {
+	p, ok := fc.Presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q (see --list-presets)", name)
+	}
+	explicit := map[string]bool{}
+	set.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var applied []string
+	for _, k := range keys {
+		if k == "preset" || set.Lookup(k) == nil {
+			return nil, fmt.Errorf("preset %q: unknown flag %q", name, k)
+		}
+		if explicit[k] {
+			continue
+		}
+		values, isList := p[k].([]any)
+		if !isList {
+			values = []any{p[k]}
+		}
+		for _, v := range values {
+			if err := set.Set(k, fmt.Sprint(v)); err != nil {
+				return nil, fmt.Errorf("preset %q: --%s: %w", name, k, err)
+			}
+		}
+		applied = append(applied, k)
+	}
+	return applied, nil
+}
+
+// printPresets lists the configured presets with the flags they expand to.
+func printPresets(fc fileConfig) {
+	if len(fc.Presets) == 0 {
+		fmt.Println("No presets defined. Add a presets section to the config file.")
+		return
+	}
+	names := make([]string, 0, len(fc.Presets))
+	for n := range fc.Presets {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		var args []string
+		for k, v := range fc.Presets[n] {
+			args = append(args, fmt.Sprintf("--%s=%v", k, v))
+		}
+		sort.Strings(args)
+		fmt.Printf("%s: %s\n", n, strings.Join(args, " "))
+	}
+}
+
+// configPath returns the config file location, e.g. ~/.config/lazydevops/config.yaml on Linux.
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "lazydevops", "config.yaml"), nil
+}
+
+// loadFileConfig reads the config file; a missing file yields an empty config.
+func loadFileConfig() (fileConfig, error) {
+	var fc fileConfig
+	p, err := configPath()
+	if err != nil {
+		return fc, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fc, nil
+	}
+	if err != nil {
+		return fc, err
+	}
+	if err := yaml.Unmarshal(b, &fc); err != nil {
+		return fc, fmt.Errorf("invalid config file %s: %w", p, err)
+	}
+	return fc, nil
+}
+
+// setFileConfig sets top-level keys of the config file to values, keeping the rest of
+// the file including its comments. A missing file is created from configTemplate. The
+// file is replaced atomically and is readable by the owner only.
+func setFileConfig(values map[string]string) (string, error) {
+	p, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		b = []byte(fmt.Sprintf(configTemplate, templateLine("org", values["org"]), templateLine("project", values["project"])))
+	} else if err != nil {
+		return "", err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return "", fmt.Errorf("invalid config file %s: %w", p, err)
+	}
+	var prefix []byte
+	if doc.Kind == 0 {
+		// an empty or comment-only file: the keys go on top of the comments
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+		prefix = b
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("invalid config file %s: expected a mapping of settings", p)
+	}
+	keys := slices.Sorted(maps.Keys(values))
+	for _, k := range keys {
+		setMappingValue(root, k, values[k])
+	}
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", err
+	}
+	if len(prefix) > 0 {
+		out.WriteString("\n")
+		out.Write(prefix)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return "", err
+	}
+	return p, writeFileAtomic(p, out.Bytes(), 0o600)
+}
+
+// setMappingValue sets key of the mapping node m to the string value, replacing the
+// value in place so the comments around it are kept.
+func setMappingValue(m *yaml.Node, key, value string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			v := m.Content[i+1]
+			v.Kind, v.Tag, v.Style, v.Value, v.Content = yaml.ScalarNode, "!!str", 0, value, nil
+			return
+		}
+	}
+	m.Content = append(m.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+}
+
+// templateLine renders a key of configTemplate, commented out with a placeholder when
+// the value is empty.
+func templateLine(key, v string) string {
+	if v == "" {
+		return "# " + key + ": my" + key
+	}
+	b, _ := yaml.Marshal(map[string]string{key: v})
+	return strings.TrimSpace(string(b))
+}
+
+// configTemplate is the file written by "config init". %s are the org and project lines.
+const configTemplate = `# LazyDevOps configuration. Command-line flags override these values.
+%s
+%s
+# Azure DevOps Server (on-premises) collection URL, like --base-url:
+# base-url: https://tfs.corp.local/tfs/DefaultCollection
+# Defaults for --top, --api-version and --output:
+# top: 50
+# api-version: "7.1-preview.1"
+# output: table
+# Prefer the LAZY_DEV_OPS_PAT environment variable; a PAT here is used when it is unset.
+# pat: ""
+# Per-organization API versions:
+# orgs:
+#   legacy-tenant:
+#     api-version: "6.0"
+# Profiles for other organizations, selected with --profile or LAZY_DEV_OPS_PROFILE:
+# profiles:
+#   client:
+#     org: contoso
+#     project: Web
+#     pat-env: CONTOSO_PAT
+# Named flag sets for --preset:
+# presets:
+#   triage:
+#     my-work: true
+#     sort: checks,created
+`
+
+// runConfigInit runs "config init": it scaffolds a commented config file.
+func runConfigInit(args []string) {
+	set := flag.NewFlagSet("config init", flag.ExitOnError)
+	org := set.String("org", "", "Organization to write into the file")
+	project := set.String("project", "", "Project to write into the file")
+	force := set.Bool("force", false, "Overwrite an existing config file")
+	set.Parse(args)
+
+	p, err := configPath()
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	if _, err := os.Stat(p); err == nil && !*force {
+		log.Fatalf("Error: %s already exists; edit it, or pass --force to start over\n", p)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	content := fmt.Sprintf(configTemplate, templateLine("org", *org), templateLine("project", *project))
+	if err := writeFileAtomic(p, []byte(content), 0o600); err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	fmt.Println("Wrote", p)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetFileConfigKeepsComments(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		existing *string
+		want     []string
+	}{
+		{
+			name: "existing settings",
+			existing: ptr(`# my settings
+org: old # the old tenant
+# legacy collection
+orgs:
+  legacy:
+    api-version: "6.0"
+`),
+			want: []string{"# my settings", "org: contoso # the old tenant", "# legacy collection", `api-version: "6.0"`, "project: \"2024\"", "  legacy:"},
+		},
+		{
+			name:     "comments only",
+			existing: ptr("# nothing yet\n"),
+			want:     []string{"org: contoso", "project: \"2024\"", "# nothing yet"},
+		},
+		{
+			name: "missing file",
+			want: []string{"# LazyDevOps configuration.", "org: contoso", "project: \"2024\"", "# base-url:"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv("HOME", t.TempDir())
+			p, err := configPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.existing != nil {
+				os.MkdirAll(filepath.Dir(p), 0o700)
+				if err := os.WriteFile(p, []byte(*tc.existing), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := setFileConfig(map[string]string{"org": "contoso", "project": "2024"}); err != nil {
+				t.Fatal(err)
+			}
+			b, err := os.ReadFile(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(string(b), w) {
+					t.Errorf("config file lacks %q:\n%s", w, b)
+				}
+			}
+			fc, err := loadFileConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fc.Org != "contoso" || fc.Project != "2024" {
+				t.Errorf("loaded org %q, project %q; want contoso, 2024", fc.Org, fc.Project)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
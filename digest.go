@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// --channel-format values.
+const (
+	channelSlack = "slack"
+	channelTeams = "teams"
+)
+
+// slackSectionLimit is the most characters Slack accepts in a section block, and
+// slackBlockLimit the most blocks in a message.
+const (
+	slackSectionLimit = 3000
+	slackBlockLimit   = 50
+)
+
+// digestPR is one PR line of the digest.
+type digestPR struct {
+	ID     int
+	Title  string
+	URL    string
+	Author string
+	Age    string
+	Checks string
+	// Waiting are the reviewers who have not voted yet, required ones marked.
+	Waiting []string
+}
+
+// digestGroup is the section of one repository.
+type digestGroup struct {
+	Repo string
+	PRs  []digestPR
+}
+
+// buildDigest groups recs by repository, ordered by --group-sort.
+func buildDigest(cfg config, recs []prRecord) []digestGroup {
+	cfg.GroupBy = groupByRepo
+	var groups []digestGroup
+	for _, g := range groupRecords(cfg, recs) {
+		dg := digestGroup{Repo: g.Key}
+		for _, r := range g.Recs {
+			p := digestPR{
+				ID:     r.PullRequestID,
+				Title:  r.Title,
+				URL:    r.Links.Web.Href,
+				Author: r.CreatedBy.DisplayName,
+				Age:    relTime(cfg, r.CreationDate.Time),
+				Checks: r.Checks,
+			}
+			for _, rv := range r.Reviewers {
+				if rv.Vote != 0 || rv.HasDeclined || rv.ID == r.CreatedBy.ID {
+					continue
+				}
+				name := rv.DisplayName
+				if rv.IsRequired {
+					name += " (required)"
+				}
+				p.Waiting = append(p.Waiting, name)
+			}
+			dg.PRs = append(dg.PRs, p)
+		}
+		groups = append(groups, dg)
+	}
+	return groups
+}
+
+// slackEscape escapes the characters Slack's mrkdwn treats as control characters.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// slackDigest renders the digest as a Slack Block Kit message: a header and a section
+// per repository, split when a section gets too long.
+func slackDigest(title string, groups []digestGroup) map[string]any {
+	blocks := []any{map[string]any{"type": "header", "text": map[string]any{"type": "plain_text", "text": title}}}
+	section := func(text string) any {
+		return map[string]any{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": text}}
+	}
+	for _, g := range groups {
+		text := fmt.Sprintf("*%s* (%d)", slackEscape(g.Repo), len(g.PRs))
+		for _, p := range g.PRs {
+			line := fmt.Sprintf("• <%s|#%d %s> by %s, %s, checks: %s", p.URL, p.ID, slackEscape(p.Title), slackEscape(p.Author), p.Age, p.Checks)
+			if len(p.Waiting) > 0 {
+				line += " — waiting on " + slackEscape(strings.Join(p.Waiting, ", "))
+			}
+			if len(text)+1+len(line) > slackSectionLimit {
+				blocks = append(blocks, section(text))
+				text = ""
+			}
+			text = strings.TrimPrefix(text+"\n"+line, "\n")
+		}
+		blocks = append(blocks, section(text))
+	}
+	// keep room for the note about left out sections
+	if len(blocks) > slackBlockLimit {
+		omitted := len(blocks) - (slackBlockLimit - 1)
+		blocks = blocks[:slackBlockLimit-1]
+		blocks = append(blocks, map[string]any{"type": "context", "elements": []any{
+			map[string]any{"type": "mrkdwn", "text": fmt.Sprintf("%d more sections left out", omitted)},
+		}})
+	}
+	return map[string]any{"text": title, "blocks": blocks}
+}
+
+// teamsDigest renders the digest as an Adaptive Card message for a Teams incoming
+// webhook or workflow.
+func teamsDigest(title string, groups []digestGroup) map[string]any {
+	body := []any{map[string]any{"type": "TextBlock", "text": title, "size": "Large", "weight": "Bolder", "wrap": true}}
+	for _, g := range groups {
+		body = append(body, map[string]any{"type": "TextBlock", "text": fmt.Sprintf("%s (%d)", g.Repo, len(g.PRs)), "weight": "Bolder", "spacing": "Medium", "wrap": true})
+		for _, p := range g.PRs {
+			line := fmt.Sprintf("[#%d %s](%s) by %s, %s, checks: %s", p.ID, p.Title, p.URL, p.Author, p.Age, p.Checks)
+			if len(p.Waiting) > 0 {
+				line += " — waiting on " + strings.Join(p.Waiting, ", ")
+			}
+			body = append(body, map[string]any{"type": "TextBlock", "text": "- " + line, "spacing": "None", "wrap": true})
+		}
+	}
+	return map[string]any{
+		"type": "message",
+		"attachments": []any{map[string]any{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"msteams": map[string]any{"width": "Full"},
+				"body":    body,
+			},
+		}},
+	}
+}
+
+// postWebhook posts payload as JSON to the webhook at endpoint.
+func postWebhook(cfg config, endpoint string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient(cfg, 15*time.Second).Post(endpoint, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &httpStatusError{Code: resp.StatusCode}
+	}
+	return nil
+}
+
+// channelFormat picks the payload format for a webhook URL when --channel-format is
+// not given: Slack for hooks.slack.com, Teams otherwise.
+func channelFormat(format, endpoint string) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+	if u, err := url.Parse(endpoint); err == nil && strings.HasSuffix(u.Hostname(), "slack.com") {
+		return channelSlack
+	}
+	return channelTeams
+}
+
+// runDigest runs "digest": it posts the open PRs, grouped by repository with their age,
+// checks and the reviewers they wait on, to a Slack or Teams webhook, or prints the
+// payload without --post-to.
+func runDigest(args []string) {
+	postTo := flag.String("post-to", "", "Incoming webhook URL to post the digest to (default: print the payload)")
+	format := flag.String("channel-format", "", "Payload format: slack (Block Kit) or teams (Adaptive Card); default from the webhook URL")
+	cfg := getConfig(args)
+	if len(cfg.Args) > 0 {
+		failUsage("unexpected argument " + cfg.Args[0])
+	}
+	channel := channelFormat(*format, *postTo)
+	if channel != channelSlack && channel != channelTeams {
+		failUsage("--channel-format must be slack or teams")
+	}
+
+	prs, me, err := fetchSelected(cfg)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	errs := newEnrichErrors()
+	recs := finishRecords(cfg, prs, me, errs)
+	reportEnrichErrors(cfg, errs)
+
+	title := "PR digest: " + summaryLine(recs)
+	if len(cfg.Projects) == 1 {
+		title = fmt.Sprintf("PR digest for %s: %s", cfg.Project, summaryLine(recs))
+	}
+	groups := buildDigest(cfg, recs)
+	var payload map[string]any
+	if channel == channelSlack {
+		payload = slackDigest(title, groups)
+	} else {
+		payload = teamsDigest(title, groups)
+	}
+
+	if *postTo == "" {
+		if err := printJSON(os.Stdout, payload); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		return
+	}
+	if err := postWebhook(cfg, *postTo, payload); err != nil {
+		log.Fatalln("Error: posting the digest:", err)
+	}
+	fmt.Printf("Posted the digest of %d pull requests.\n", len(recs))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"io"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// Setting sources reported by --explain-config. Presets and environment variables are
+// reported with their name, e.g. "preset triage" or "env NO_COLOR".
+const (
+	sourceDefault = "default"
+	sourceFlag    = "flag"
+	sourceFile    = "file"
+	// sourceGitRemote marks values taken from the origin remote of the current checkout.
+	sourceGitRemote = "git remote"
+)
+
+// settingPAT is the pseudo setting name under which the PAT's source is recorded.
+const settingPAT = "pat"
+
+// configSources maps setting names (flag names plus settingPAT) to where their value came from.
+type configSources map[string]string
+
+// newConfigSources marks every flag of set as default, except those given on the command line.
+func newConfigSources(set *flag.FlagSet) configSources {
+	src := configSources{}
+	set.VisitAll(func(f *flag.Flag) { src[f.Name] = sourceDefault })
+	set.Visit(func(f *flag.Flag) { src[f.Name] = sourceFlag })
+	return src
+}
+
+// printConfigExplanation lists every setting with its resolved value and source.
+// The PAT itself is never printed.
+func printConfigExplanation(w io.Writer, set *flag.FlagSet, src configSources, pat string) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{"Setting", "Value", "Source"})
+
+	redacted := "(not set)"
+	if pat != "" {
+		redacted = "********"
+	}
+	t.AppendRow(table.Row{settingPAT, redacted, src[settingPAT]})
+	set.VisitAll(func(f *flag.Flag) {
+		switch f.Name {
+		case "explain-config", "list-presets", "init":
+			return
+		}
+		v := f.Value.String()
+		if v == "" {
+			v = `""`
+		}
+		t.AppendRow(table.Row{f.Name, v, src[f.Name]})
+	})
+	t.Render()
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// gitRemote is an Azure DevOps repository identified from a git remote URL.
+type gitRemote struct {
+	Org, Project, Repo string
+}
+
+// git runs a git command in the current directory and returns its trimmed output.
+func git(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// currentBranch returns the checked-out branch as a full ref, e.g. refs/heads/feature/x.
+func currentBranch() (string, error) {
+	b, err := git("symbolic-ref", "--quiet", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("no branch checked out (detached HEAD?): %w", err)
+	}
+	return b, nil
+}
+
+// originRemote parses the URL of the "origin" remote of the current repository.
+// baseURL is the --base-url collection, if any.
+func originRemote(baseURL string) (gitRemote, error) {
+	u, err := git("remote", "get-url", "origin")
+	if err != nil {
+		return gitRemote{}, err
+	}
+	return parseRemoteURL(u, baseURL)
+}
+
+// collectionName returns the name of the Azure DevOps Server collection at baseURL,
+// its last path segment.
+func collectionName(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("%q is not an http(s) URL", baseURL)
+	}
+	path := strings.Trim(u.Path, "/")
+	if path == "" {
+		return "", fmt.Errorf("%q names no collection, e.g. https://%s/tfs/DefaultCollection", baseURL, u.Host)
+	}
+	return path[strings.LastIndex(path, "/")+1:], nil
+}
+
+// parseRemoteURL recognizes the HTTPS and SSH remote URL forms of Azure DevOps:
+//
+//	https://dev.azure.com/{org}/{project}/_git/{repo}   (optionally with user@)
+//	https://{org}.visualstudio.com/[DefaultCollection/]{project}/_git/{repo}
+//	git@ssh.dev.azure.com:v3/{org}/{project}/{repo}
+//	{org}@vs-ssh.visualstudio.com:v3/{org}/{project}/{repo}
+//
+// and, given the collection URL baseURL of an Azure DevOps Server, its HTTPS and SSH
+// forms {baseURL}/{project}/_git/{repo}, with the collection name as org.
+func parseRemoteURL(raw, baseURL string) (gitRemote, error) {
+	bad := fmt.Errorf("%q is not an Azure DevOps remote", raw)
+	if r, ok := parseServerRemote(raw, baseURL); ok {
+		return r, nil
+	}
+	if i := strings.Index(raw, ":v3/"); i >= 0 && !strings.Contains(raw, "://") {
+		parts := strings.Split(raw[i+len(":v3/"):], "/")
+		if len(parts) != 3 {
+			return gitRemote{}, bad
+		}
+		return unescapeRemote(parts[0], parts[1], parts[2])
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return gitRemote{}, bad
+	}
+	path := strings.Split(strings.Trim(u.EscapedPath(), "/"), "/")
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "dev.azure.com" && len(path) == 4 && path[2] == "_git":
+		return unescapeRemote(path[0], path[1], path[3])
+	case strings.HasSuffix(host, ".visualstudio.com"):
+		org := strings.TrimSuffix(host, ".visualstudio.com")
+		if len(path) == 4 && strings.EqualFold(path[0], "DefaultCollection") {
+			path = path[1:]
+		}
+		if len(path) == 3 && path[1] == "_git" {
+			return unescapeRemote(org, path[0], path[2])
+		}
+	}
+	return gitRemote{}, bad
+}
+
+// parseServerRemote recognizes a remote of the Azure DevOps Server collection at baseURL.
+// Only host and path are compared, so SSH remotes on another port match as well.
+func parseServerRemote(raw, baseURL string) (gitRemote, bool) {
+	if baseURL == "" {
+		return gitRemote{}, false
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return gitRemote{}, false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !strings.EqualFold(u.Hostname(), base.Hostname()) {
+		return gitRemote{}, false
+	}
+	p := strings.Trim(u.EscapedPath(), "/")
+	basePath := strings.Trim(base.EscapedPath(), "/")
+	// collection paths are case-insensitive on the server
+	if len(p) <= len(basePath) || !strings.EqualFold(p[:len(basePath)], basePath) || p[len(basePath)] != '/' {
+		return gitRemote{}, false
+	}
+	path := strings.Split(p[len(basePath)+1:], "/")
+	if len(path) != 3 || path[1] != "_git" {
+		return gitRemote{}, false
+	}
+	collection := basePath[strings.LastIndex(basePath, "/")+1:]
+	r, err := unescapeRemote(collection, path[0], path[2])
+	return r, err == nil
+}
+
+// unescapeRemote decodes the org, project and repo segments of a remote URL.
+func unescapeRemote(org, project, repo string) (gitRemote, error) {
+	parts := []string{org, project, strings.TrimSuffix(repo, ".git")}
+	for i, p := range parts {
+		s, err := url.PathUnescape(p)
+		if err != nil {
+			return gitRemote{}, err
+		}
+		parts[i] = s
+	}
+	return gitRemote{Org: parts[0], Project: parts[1], Repo: parts[2]}, nil
+}
+
+// branchRef turns a branch name into a full ref; full refs are returned as they are.
+func branchRef(name string) string {
+	if strings.HasPrefix(name, "refs/") {
+		return name
+	}
+	return "refs/heads/" + name
+}
@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestParseRemoteURL(t *testing.T) {
+	const server = "https://tfs.corp.local/tfs/DefaultCollection"
+	for _, tc := range []struct {
+		name, raw, baseURL string
+		want               gitRemote
+		wantErr            bool
+	}{
+		{name: "https", raw: "https://dev.azure.com/contoso/Web/_git/site", want: gitRemote{Org: "contoso", Project: "Web", Repo: "site"}},
+		{name: "https with user", raw: "https://contoso@dev.azure.com/contoso/Web/_git/site", want: gitRemote{Org: "contoso", Project: "Web", Repo: "site"}},
+		{name: "https with .git", raw: "https://dev.azure.com/contoso/Web/_git/site.git", want: gitRemote{Org: "contoso", Project: "Web", Repo: "site"}},
+		{name: "escaped names", raw: "https://dev.azure.com/contoso/My%20Project/_git/My%20Repo", want: gitRemote{Org: "contoso", Project: "My Project", Repo: "My Repo"}},
+		{name: "ssh", raw: "git@ssh.dev.azure.com:v3/contoso/Web/site", want: gitRemote{Org: "contoso", Project: "Web", Repo: "site"}},
+		{name: "ssh escaped", raw: "git@ssh.dev.azure.com:v3/contoso/My%20Project/site", want: gitRemote{Org: "contoso", Project: "My Project", Repo: "site"}},
+		{name: "legacy ssh", raw: "contoso@vs-ssh.visualstudio.com:v3/contoso/Web/site", want: gitRemote{Org: "contoso", Project: "Web", Repo: "site"}},
+		{name: "legacy https", raw: "https://contoso.visualstudio.com/Web/_git/site", want: gitRemote{Org: "contoso", Project: "Web", Repo: "site"}},
+		{name: "legacy https with collection", raw: "https://contoso.visualstudio.com/DefaultCollection/Web/_git/site", want: gitRemote{Org: "contoso", Project: "Web", Repo: "site"}},
+		{name: "server https", raw: "https://tfs.corp.local/tfs/DefaultCollection/Web/_git/site", baseURL: server, want: gitRemote{Org: "DefaultCollection", Project: "Web", Repo: "site"}},
+		{name: "server collection case", raw: "https://TFS.corp.local/tfs/defaultcollection/Web/_git/site", baseURL: server, want: gitRemote{Org: "DefaultCollection", Project: "Web", Repo: "site"}},
+		{name: "server ssh", raw: "ssh://tfs.corp.local:22/tfs/DefaultCollection/Web/_git/site", baseURL: server, want: gitRemote{Org: "DefaultCollection", Project: "Web", Repo: "site"}},
+		{name: "services remote with a base URL", raw: "https://dev.azure.com/contoso/Web/_git/site", baseURL: server, want: gitRemote{Org: "contoso", Project: "Web", Repo: "site"}},
+
+		{name: "github", raw: "https://github.com/contoso/site.git", wantErr: true},
+		{name: "missing _git", raw: "https://dev.azure.com/contoso/Web/site", wantErr: true},
+		{name: "too short", raw: "https://dev.azure.com/contoso/_git/site", wantErr: true},
+		{name: "ssh too short", raw: "git@ssh.dev.azure.com:v3/contoso/site", wantErr: true},
+		{name: "bad escape", raw: "https://dev.azure.com/contoso/Web%zz/_git/site", wantErr: true},
+		{name: "server without a base URL", raw: "https://tfs.corp.local/tfs/DefaultCollection/Web/_git/site", wantErr: true},
+		{name: "other collection", raw: "https://tfs.corp.local/tfs/Other/Web/_git/site", baseURL: server, wantErr: true},
+		{name: "empty", raw: "", wantErr: true},
+	} {
+		got, err := parseRemoteURL(tc.raw, tc.baseURL)
+		switch {
+		case tc.wantErr && err == nil:
+			t.Errorf("%s: parseRemoteURL(%q) = %+v, want an error", tc.name, tc.raw, got)
+		case !tc.wantErr && err != nil:
+			t.Errorf("%s: parseRemoteURL(%q): %v", tc.name, tc.raw, err)
+		case !tc.wantErr && got != tc.want:
+			t.Errorf("%s: parseRemoteURL(%q) = %+v, want %+v", tc.name, tc.raw, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// --group-by values.
+const (
+	groupByRole   = "role"
+	groupByRepo   = "repo"
+	groupByAuthor = "author"
+	groupByTarget = "target-branch"
+)
+
+// --group-sort values, ordering the sections of non-role groupings.
+const (
+	groupSortCount   = "count"
+	groupSortAge     = "age"
+	groupSortFailing = "failing"
+)
+
+// prGroup is one section of grouped output. Key is empty when grouping is off.
+type prGroup struct {
+	Key  string
+	Recs []prRecord
+}
+
+func groupKey(cfg config, r prRecord) string {
+	switch cfg.GroupBy {
+	case groupByRole:
+		return r.Role
+	case groupByRepo:
+		return r.Repository.Name
+	case groupByAuthor:
+		return r.CreatedBy.DisplayName
+	case groupByTarget:
+		return refShort(r.TargetRefName)
+	}
+	return ""
+}
+
+// groupRecords splits recs into sections per --group-by, keeping the existing order within
+// each section. Role groups always list authored PRs before review requests; other
+// groupings are ordered by --group-sort.
+func groupRecords(cfg config, recs []prRecord) []prGroup {
+	if cfg.GroupBy == "" {
+		return []prGroup{{Recs: recs}}
+	}
+	var groups []prGroup
+	index := map[string]int{}
+	if cfg.GroupBy == groupByRole {
+		groups = []prGroup{{Key: roleAuthor}, {Key: roleReviewer}}
+		index[roleAuthor], index[roleReviewer] = 0, 1
+	}
+	for _, r := range recs {
+		k := groupKey(cfg, r)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, prGroup{Key: k})
+		}
+		groups[i].Recs = append(groups[i].Recs, r)
+	}
+	out := groups[:0]
+	for _, g := range groups {
+		if len(g.Recs) > 0 {
+			out = append(out, g)
+		}
+	}
+	if cfg.GroupBy != groupByRole {
+		sortGroups(cfg.GroupSort, out)
+	}
+	return out
+}
+
+// sortGroups orders sections busiest first by the given metric: PR count, age of the
+// oldest PR, or number of PRs with failing checks. Ties are broken by name.
+func sortGroups(metric string, groups []prGroup) {
+	score := func(g prGroup) float64 {
+		switch metric {
+		case groupSortAge:
+			oldest := time.Now()
+			for _, r := range g.Recs {
+				if !r.CreationDate.IsZero() && r.CreationDate.Before(oldest) {
+					oldest = r.CreationDate.Time
+				}
+			}
+			return time.Since(oldest).Seconds()
+		case groupSortFailing:
+			n := 0
+			for _, r := range g.Recs {
+				if r.Checks == "Failed" {
+					n++
+				}
+			}
+			return float64(n)
+		default:
+			return float64(len(g.Recs))
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b prGroup) int {
+		if c := cmp.Compare(score(b), score(a)); c != 0 {
+			return c
+		}
+		return strings.Compare(strings.ToLower(a.Key), strings.ToLower(b.Key))
+	})
+}
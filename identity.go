@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Roles reported in --my-work mode.
+const (
+	roleAuthor   = "author"
+	roleReviewer = "reviewer"
+)
+
+// userIdentity is the user the PAT authenticates as.
+type userIdentity struct {
+	ID          string
+	DisplayName string
+	UniqueName  string
+	// Aliases are other unique names the user appears under, e.g. the mail address
+	// next to the UPN, or DOMAIN\user on on-premises-synced accounts.
+	Aliases []string
+}
+
+// resolveMe looks up the authenticated user via the organization's connectionData endpoint.
+func resolveMe(cfg config) (userIdentity, error) {
+	endpoint := apiClient(cfg, 0).OrgURL() + "/_apis/connectionData"
+	var cd struct {
+		AuthenticatedUser struct {
+			ID                  string `json:"id"`
+			ProviderDisplayName string `json:"providerDisplayName"`
+			Properties          struct {
+				Account struct {
+					Value string `json:"$value"`
+				} `json:"Account"`
+			} `json:"properties"`
+		} `json:"authenticatedUser"`
+	}
+	if err := apiGet(cfg, endpoint, &cd); err != nil {
+		return userIdentity{}, err
+	}
+	u := cd.AuthenticatedUser
+	if u.ID == "" {
+		return userIdentity{}, fmt.Errorf("connectionData returned no authenticated user")
+	}
+	me := userIdentity{ID: u.ID, DisplayName: u.ProviderDisplayName, UniqueName: u.Properties.Account.Value}
+	aliases, err := fetchIdentityAliases(cfg, u.ID)
+	if err != nil {
+		debugLog.Printf("identity aliases for %s: %v", u.ID, err)
+	}
+	me.addAliases(aliases...)
+	me.addAliases(cfg.IdentityAliases...)
+	return me, nil
+}
+
+// fetchIdentityAliases returns the account names recorded for the identity with the
+// given ID: its account, mail address and DOMAIN\account form.
+func fetchIdentityAliases(cfg config, id string) ([]string, error) {
+	q := url.Values{}
+	q.Set("identityIds", id)
+	endpoint := apiClient(cfg, 0).IdentityURL("_apis/identities", q)
+	type property struct {
+		Value string `json:"$value"`
+	}
+	var resp struct {
+		Value []struct {
+			Properties struct {
+				Account        property `json:"Account"`
+				Mail           property `json:"Mail"`
+				Domain         property `json:"Domain"`
+				DirectoryAlias property `json:"DirectoryAlias"`
+			} `json:"properties"`
+		} `json:"value"`
+	}
+	if err := apiGet(cfg, endpoint, &resp); err != nil {
+		return nil, err
+	}
+	var aliases []string
+	for _, v := range resp.Value {
+		p := v.Properties
+		aliases = append(aliases, p.Account.Value, p.Mail.Value)
+		if p.Domain.Value != "" && p.DirectoryAlias.Value != "" {
+			aliases = append(aliases, p.Domain.Value+`\`+p.DirectoryAlias.Value)
+		}
+	}
+	return aliases, nil
+}
+
+// addAliases records names as aliases of me, skipping empty names and duplicates.
+func (me *userIdentity) addAliases(names ...string) {
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n == "" || strings.EqualFold(n, me.UniqueName) || slices.ContainsFunc(me.Aliases, func(a string) bool { return strings.EqualFold(a, n) }) {
+			continue
+		}
+		me.Aliases = append(me.Aliases, n)
+	}
+}
+
+// is reports whether the identity with the given ID and unique name is me, matching
+// the unique name against my aliases too.
+func (me userIdentity) is(id, uniqueName string) bool {
+	if id != "" && strings.EqualFold(id, me.ID) {
+		return true
+	}
+	if uniqueName == "" {
+		return false
+	}
+	if me.UniqueName != "" && strings.EqualFold(uniqueName, me.UniqueName) {
+		return true
+	}
+	return slices.ContainsFunc(me.Aliases, func(a string) bool { return strings.EqualFold(a, uniqueName) })
+}
+
+func isAuthor(pr pullRequest, me userIdentity) bool {
+	return me.is(pr.CreatedBy.ID, pr.CreatedBy.UniqueName)
+}
+
+// needsReviewBy reports whether me is a reviewer on pr who has not voted yet.
+func needsReviewBy(pr pullRequest, me userIdentity) bool {
+	for _, r := range pr.Reviewers {
+		if me.is(r.ID, r.UniqueName) && r.Vote == 0 && !r.HasDeclined {
+			return true
+		}
+	}
+	return false
+}
+
+// roleOf classifies pr for --my-work; authorship wins over being a reviewer.
+func roleOf(pr pullRequest, me userIdentity) string {
+	if isAuthor(pr, me) {
+		return roleAuthor
+	}
+	if needsReviewBy(pr, me) {
+		return roleReviewer
+	}
+	return ""
+}
+
+// filterMyWork keeps PRs created by me or waiting for my review.
+func filterMyWork(prs []pullRequest, me userIdentity) []pullRequest {
+	out := prs[:0]
+	for _, pr := range prs {
+		if roleOf(pr, me) != "" {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
+
+// filterToReview keeps PRs where me is a reviewer who has not voted yet.
+func filterToReview(prs []pullRequest, me userIdentity) []pullRequest {
+	out := prs[:0]
+	for _, pr := range prs {
+		if needsReviewBy(pr, me) {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
+
+// filterMine keeps PRs created by me.
+func filterMine(prs []pullRequest, me userIdentity) []pullRequest {
+	out := prs[:0]
+	for _, pr := range prs {
+		if isAuthor(pr, me) {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
+
+// authorMatches reports whether the PR author matches one of want: an exact unique name
+// (email) or part of the display name, ignoring case.
+func authorMatches(author identity, want []string) bool {
+	return slices.ContainsFunc(want, func(w string) bool {
+		return strings.EqualFold(author.UniqueName, w) ||
+			strings.Contains(strings.ToLower(author.DisplayName), strings.ToLower(w))
+	})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestUserIdentityIs(t *testing.T) {
+	me := userIdentity{ID: "0a1b", UniqueName: "jane@contoso.onmicrosoft.com"}
+	me.addAliases("jane.doe@contoso.com", `CONTOSO\jdoe`)
+	for _, tc := range []struct {
+		name           string
+		id, uniqueName string
+		want           bool
+	}{
+		{"id", "0A1B", "", true},
+		{"upn", "", "jane@contoso.onmicrosoft.com", true},
+		{"upn ignoring case", "", "Jane@Contoso.OnMicrosoft.com", true},
+		{"mail alias", "ffff", "JANE.DOE@contoso.com", true},
+		{"domain alias", "", `contoso\JDOE`, true},
+		{"someone else", "ffff", "john@contoso.com", false},
+		{"other domain", "", `FABRIKAM\jdoe`, false},
+		{"nothing to match", "", "", false},
+	} {
+		if got := me.is(tc.id, tc.uniqueName); got != tc.want {
+			t.Errorf("%s: is(%q, %q) = %v, want %v", tc.name, tc.id, tc.uniqueName, got, tc.want)
+		}
+	}
+}
+
+func TestAddAliasesDedups(t *testing.T) {
+	me := userIdentity{UniqueName: "jane@contoso.com"}
+	// as resolveMe does: the aliases from the API, then --identity-alias
+	me.addAliases("jane@contoso.com", "Jane.Doe@contoso.com", "", `CONTOSO\jdoe`)
+	me.addAliases(" jane.doe@CONTOSO.com ", `contoso\JDOE`, "JANE@contoso.com", "jd@fabrikam.com")
+	want := []string{"Jane.Doe@contoso.com", `CONTOSO\jdoe`, "jd@fabrikam.com"}
+	if !slices.Equal(me.Aliases, want) {
+		t.Errorf("Aliases = %q, want %q", me.Aliases, want)
+	}
+}
+
+func TestIdentityCallsUseConfiguredAPIVersion(t *testing.T) {
+	var versions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		versions = append(versions, r.URL.Query().Get("api-version"))
+		w.Write([]byte(`{"value":[]}`))
+	}))
+	defer srv.Close()
+
+	cfg := config{BaseURL: srv.URL, ApiVer: "5.0"}
+	fetchIdentityAliases(cfg, "0a1b")
+	findIdentity(cfg, "jane@contoso.com")
+	identityNames(cfg, []string{"0a1b"})
+	if len(versions) != 3 {
+		t.Fatalf("%d requests, want 3", len(versions))
+	}
+	for _, v := range versions {
+		if v != "5.0" {
+			t.Errorf("api-version = %q, want the configured 5.0", v)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+)
+
+// keyringService is the service name PATs are stored under in the OS keyring,
+// one entry per organization.
+const keyringService = "lazydevops"
+
+// keyringPAT returns the PAT stored for org, or "" when there is none or the
+// keyring is unavailable.
+func keyringPAT(org string) string {
+	if org == "" {
+		return ""
+	}
+	pat, err := keyring.Get(keyringService, strings.ToLower(org))
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		debugLog.Printf("keyring: %v", err)
+	}
+	return pat
+}
+
+// authOrg resolves the organization for the auth commands from --org, the git
+// remote of the current directory or the config file. With --base-url (or the config
+// file's base-url) the collection name stands in for the organization.
+func authOrg(org, baseURL string) (string, string) {
+	fc, _ := loadFileConfig()
+	if baseURL == "" {
+		baseURL = fc.BaseURL
+	}
+	if org != "" {
+		return org, baseURL
+	}
+	if remote, err := originRemote(baseURL); err == nil {
+		return remote.Org, baseURL
+	}
+	if fc.Org != "" {
+		return fc.Org, baseURL
+	}
+	if baseURL != "" {
+		collection, err := collectionName(baseURL)
+		if err != nil {
+			failUsage("--base-url: " + err.Error())
+		}
+		return collection, baseURL
+	}
+	failUsage("--org is required")
+	return "", ""
+}
+
+// runAuthLogin runs "auth login": it reads a PAT (hidden on a terminal, otherwise the
+// first line of stdin), checks it and stores it in the OS keyring.
+func runAuthLogin(args []string) {
+	set := flag.NewFlagSet("auth login", flag.ExitOnError)
+	orgFlag := set.String("org", "", "Organization the PAT belongs to (default: from the git remote or config file)")
+	baseURLFlag := set.String("base-url", "", "Azure DevOps Server collection URL the PAT belongs to (default: from the config file)")
+	set.Parse(args)
+	org, baseURL := authOrg(*orgFlag, *baseURLFlag)
+
+	var pat string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Printf("PAT for %s (input hidden): ", org)
+		b, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		pat = string(b)
+	} else {
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		pat = line
+	}
+	pat = strings.TrimSpace(pat)
+	if pat == "" {
+		failUsage("no PAT given")
+	}
+
+	me, err := resolveMe(config{Org: org, BaseURL: strings.TrimSuffix(baseURL, "/"), Pat: pat, Auth: authPAT})
+	if err != nil {
+		log.Fatalln("Error: the PAT was not accepted:", err)
+	}
+	if err := keyring.Set(keyringService, strings.ToLower(org), pat); err != nil {
+		log.Fatalln("Error: storing the PAT in the keyring:", err)
+	}
+	fmt.Printf("Logged in to %s as %s; the PAT is stored in the OS keyring.\n", org, me.DisplayName)
+}
+
+// runAuthLogout runs "auth logout": it removes the stored PAT of an organization.
+func runAuthLogout(args []string) {
+	set := flag.NewFlagSet("auth logout", flag.ExitOnError)
+	orgFlag := set.String("org", "", "Organization to log out of (default: from the git remote or config file)")
+	set.Parse(args)
+	org, _ := authOrg(*orgFlag, "")
+
+	err := keyring.Delete(keyringService, strings.ToLower(org))
+	switch {
+	case errors.Is(err, keyring.ErrNotFound):
+		fmt.Printf("No PAT stored for %s.\n", org)
+	case err != nil:
+		log.Fatalln("Error: removing the PAT from the keyring:", err)
+	default:
+		fmt.Printf("Removed the PAT for %s from the OS keyring.\n", org)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// runPRLink runs "pr link <id> --work-item W": it links work items to the PR, like the
+// Development section of a work item in the web UI. Work items already linked are
+// skipped.
+func runPRLink(args []string) {
+	var workItems stringList
+	flag.Var(&workItems, "work-item", "Work item ID to link (repeatable or comma-separated)")
+	cfg := getConfig(args)
+	id := prArg(cfg, "link")
+	if len(workItems) == 0 {
+		failUsage("--work-item is required")
+	}
+	ids := make([]int, 0, len(workItems))
+	for _, w := range workItems {
+		n, err := strconv.Atoi(w)
+		if err != nil || n <= 0 {
+			failUsage(fmt.Sprintf("--work-item: %q is not a work item ID", w))
+		}
+		ids = append(ids, n)
+	}
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	linked, err := fetchPRWorkItems(context.Background(), cfg, pr)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+
+	client := apiClient(cfg, 15*time.Second)
+	for _, wi := range ids {
+		if slices.Contains(linked, strconv.Itoa(wi)) {
+			fmt.Printf("Work item #%d is already linked to PR #%d\n", wi, pr.PullRequestID)
+			continue
+		}
+		if err := client.AddArtifactLink(context.Background(), prProject(cfg, pr), wi, pr.ArtifactURL(), "Pull Request"); err != nil {
+			log.Fatalf("Error: linking work item #%d: %v\n", wi, err)
+		}
+		fmt.Printf("Linked work item #%d to PR #%d\n", wi, pr.PullRequestID)
+	}
+}
@@ -1,283 +1,1351 @@
 package main
 
 import (
-	"encoding/base64"
-	"encoding/json"
+	"cmp"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
+	"LazyDevOps/pkg/azdo"
 	"github.com/dustin/go-humanize"
 	"github.com/jedib0t/go-pretty/v6/table"
 )
 
 const envVarPrimaryPAT = "LAZY_DEV_OPS_PAT"
 
-type prResponse struct {
-	Value []pullRequest `json:"value"`
-	Count int           `json:"count"`
-}
+// envVarProfile selects a config file profile when --profile is not given.
+const envVarProfile = "LAZY_DEV_OPS_PROFILE"
 
-type identity struct {
-	DisplayName string `json:"displayName"`
-	UniqueName  string `json:"uniqueName"`
-}
+// Exit codes beyond the usual 1 (fatal error) and 2 (usage).
+const (
+	// exitNoResults is used by --fail-if-none when nothing matched.
+	exitNoResults = 3
+	// exitEnrichmentFailed is used by --strict when any per-PR enrichment call failed.
+	exitEnrichmentFailed = 4
+	// exitRunFailed is used by --follow when the followed run did not succeed.
+	exitRunFailed = 5
+)
 
-type repositoryInfo struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
+// maxPageSize is the largest $top accepted for a single pull request list call.
+const maxPageSize = 1000
 
-type reviewer struct {
-	DisplayName string `json:"displayName"`
-	Vote        int    `json:"vote"`
-}
+// debugLog is silent unless --debug is set. It is shared with the azdo package.
+var debugLog = azdo.Debug
 
-type links struct {
-	Web struct {
-		Href string `json:"href"`
-	} `json:"web"`
+// The API types are those of the azdo package. Their JSON field names are the API's
+// and are kept as they are in --output json/ndjson and --json-out.
+type (
+	apiTime         = azdo.Time
+	identity        = azdo.Identity
+	repositoryInfo  = azdo.RepositoryInfo
+	projectInfo     = azdo.ProjectInfo
+	reviewer        = azdo.Reviewer
+	pullRequest     = azdo.PullRequest
+	prStatusContext = azdo.StatusContext
+	prStatus        = azdo.Status
+)
+
+// prRecord is a pull request together with the data gathered for it by per-PR enrichment calls.
+type prRecord struct {
+	pullRequest
+	Checks    string   `json:"checks"`
+	WorkItems []string `json:"workItems,omitempty"`
+	// Role is "author" or "reviewer" in --my-work mode.
+	Role string `json:"role,omitempty"`
+	// ApprovalGap is the number of approvals still required by the minimum-reviewers
+	// policy; nil when it was not computed or no such policy applies.
+	ApprovalGap *int `json:"approvalGap,omitempty"`
+	// ReadyToPublish is set for drafts whose checks all pass, i.e. safe to mark as ready.
+	ReadyToPublish bool `json:"readyToPublish,omitempty"`
+	// DefaultBranch is the repository's default branch ref, empty when unknown.
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+	// Comments counts the comment threads for the Comments column; nil when they
+	// were not loaded.
+	Comments *threadCounts `json:"comments,omitempty"`
+	// Updated is when the PR was last updated (see lastActivity); zero when its
+	// threads were not loaded.
+	Updated time.Time `json:"updated,omitzero"`
+	// Changed is set by --watch for PRs that are new or whose checks or votes changed
+	// since the previous refresh.
+	Changed bool `json:"-"`
 }
 
-type pullRequest struct {
-	PullRequestID int            `json:"pullRequestId"`
-	Title         string         `json:"title"`
-	Status        string         `json:"status"`
-	CreationDate  time.Time      `json:"creationDate"`
-	Repository    repositoryInfo `json:"repository"`
-	CreatedBy     identity       `json:"createdBy"`
-	SourceRefName string         `json:"sourceRefName"`
-	TargetRefName string         `json:"targetRefName"`
-	Reviewers     []reviewer     `json:"reviewers"`
-	Links         links          `json:"_links"`
+// threadCounts counts the active and resolved comment threads of a PR.
+type threadCounts struct {
+	Active   int `json:"active"`
+	Resolved int `json:"resolved"`
 }
 
-type prStatusContext struct {
-	Name  string `json:"name"`
-	Genre string `json:"genre"`
+// needsThreads reports whether the PR threads must be loaded for the comments or
+// updated columns or for sorting by update time.
+func (cfg config) needsThreads() bool {
+	cols := tableColumnNames(cfg)
+	return slices.Contains(cols, "comments") || slices.Contains(cols, "updated") ||
+		slices.ContainsFunc(cfg.Sort, func(k sortKey) bool { return k.Name == "updated" })
 }
 
-type prStatus struct {
-	State        string          `json:"state"`
-	Description  string          `json:"description"`
-	Context      prStatusContext `json:"context"`
-	TargetURL    string          `json:"targetUrl"`
-	CreationDate time.Time       `json:"creationDate"`
-	UpdatedDate  time.Time       `json:"updatedDate"`
+// needsDefaultBranch reports whether the repositories' default branches are filtered on
+// or shown: by the ⚠ marker of the table's Source->Target column, the description line,
+// the interactive mode or the defaultBranch field of the JSON and template outputs.
+func (cfg config) needsDefaultBranch() bool {
+	if cfg.NonDefaultTargetOnly || cfg.ShowDescription || cfg.TUI {
+		return true
+	}
+	switch cfg.Output {
+	case outputJSON, outputNDJSON, outputNDJSONWithErrors, outputTemplate:
+		return true
+	case outputTable:
+		return !cfg.Oneline && !cfg.CountByStatus && !cfg.StatsByAuthor &&
+			slices.Contains(tableColumnNames(cfg), "branches")
+	}
+	return false
 }
 
-type prStatusResponse struct {
-	Value []prStatus `json:"value"`
-	Count int        `json:"count"`
+// nonDefaultTarget reports whether the PR targets something other than its repository's
+// default branch; false when the default branch is unknown.
+func (r prRecord) nonDefaultTarget() bool {
+	return r.DefaultBranch != "" && !strings.EqualFold(r.TargetRefName, r.DefaultBranch)
 }
 
 type config struct {
-	Org     string
+	// Init runs the setup wizard; File is the loaded config file it starts from.
+	Init bool
+	File fileConfig
+	Org  string
+	// BaseURL is the Azure DevOps Server collection URL; empty for Azure DevOps Services.
+	BaseURL string
 	Project string
-	Pat     string
-	Top     int
-	ApiVer  string
+	// Projects are all --project values; Project is the first of them.
+	Projects []string
+	// Orgs are the organizations of a multi-organization run, nil for a single one.
+	// Org, BaseURL, Projects, Pat and ApiVer are those of the first.
+	Orgs                 []orgConnection
+	Pat                  string
+	Auth                 string
+	Top                  int
+	PageSize             int
+	ApiVer               string
+	UTC                  bool
+	FailIfNone           bool
+	Source               string
+	Target               string
+	Debug                bool
+	Strict               bool
+	OpenFailing          bool
+	Since                string
+	OlderThan            time.Duration
+	Status               string
+	SinceTime            time.Time
+	NoReviewers          bool
+	DraftsOnly           bool
+	ConflictsOnly        bool
+	NoDrafts             bool
+	NonDefaultTargetOnly bool
+	StaleCheckAge        time.Duration
+
+	ShowDescription bool
+	Oneline         bool
+	Stream          bool
+	StatsByAuthor   bool
+	CountByStatus   bool
+	Benchmark       bool
+	TUI             bool
+	Watch           watchFlag
+	Notify          bool
+	ColorTheme      string
+	NoColor         bool
+	TitleWidth      int
+	MaxTitleLines   int
+	Output          string
+	// Columns are the --columns of the table; empty for the defaults. Wide adds the
+	// description, comment and label columns to the defaults.
+	Columns []string
+	Wide    bool
+	// Template is the parsed --template of --output template.
+	Template        *template.Template
+	JSONOut         string
+	OutFile         string
+	DisplayLimit    int
+	MyWork          bool
+	MineToReview    bool
+	Mine            bool
+	Authors         []string
+	IdentityAliases []string
+	GroupBy         string
+	GroupSort       string
+	Sort            []sortKey
+	ShowGap         bool
+	GapOnly         bool
+	WorkItems       []string
+	// ShowWorkItems adds the WIs column; RequireWorkItem marks PRs without work items in it.
+	ShowWorkItems   bool
+	RequireWorkItem bool
+	Repos           []string
+
+	Pushgateway  string
+	PushJob      string
+	PushInstance string
+
+	TLSMinVersion   uint16
+	TLSCipherSuites []uint16
+
+	Concurrency    int
+	MinConcurrency int
+	MaxConcurrency int
+
+	// Args are the positional arguments given to the command, e.g. a PR ID.
+	Args []string
 }
 
 func main() {
-	cfg := getConfig()
+	runCommand(os.Args[1:])
+}
+
+// runPRList runs "prs list", which is also what a bare invocation does.
+func runPRList(args []string) {
+	cfg := getConfig(args)
+	if len(cfg.Args) > 0 {
+		failUsage("unexpected argument " + cfg.Args[0])
+	}
+
+	if cfg.Init {
+		if err := runInitWizard(cfg, cfg.File); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		return
+	}
 
-	prs, err := fetchActivePRs(cfg)
+	if cfg.TUI {
+		if err := runTUI(cfg); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		return
+	}
+	if cfg.Watch > 0 {
+		runWatch(cfg)
+		return
+	}
+
+	prs, me, err := fetchSelected(cfg)
 	if err != nil {
 		log.Fatalln("Error: ", err)
 	}
 
 	if len(prs) == 0 {
-		fmt.Println("No active pull requests found.")
+		noResults(cfg, msg(msgNoActivePRs, statusLabel(cfg.Status)))
 		return
 	}
 
-	// sort by creation date desc
-	sort.Slice(prs, func(i, j int) bool { return prs[i].CreationDate.After(prs[j].CreationDate) })
+	if cfg.Benchmark {
+		out, closeOut := openOutput(cfg)
+		printBenchmark(out, cfg, runBenchmark(cfg, prs))
+		closeOut()
+		return
+	}
+
+	errs := newEnrichErrors()
+	if cfg.Stream {
+		out, closeOut := openOutput(cfg)
+		n := streamOneline(out, cfg, prs, me, errs)
+		closeOut()
+		reportEnrichErrors(cfg, errs)
+		if n == 0 {
+			noResults(cfg, msg(msgNoMatches, statusLabel(cfg.Status)))
+		}
+		return
+	}
+	recs := finishRecords(cfg, prs, me, errs)
+	if len(recs) == 0 {
+		reportEnrichErrors(cfg, errs)
+		noResults(cfg, msg(msgNoMatches, statusLabel(cfg.Status)))
+		return
+	}
+	if cfg.JSONOut != "" {
+		if err := writeJSONFile(cfg.JSONOut, recs); err != nil {
+			log.Fatalln("Error: writing --json-out:", err)
+		}
+	}
+
+	out, closeOut := openOutput(cfg)
+	printRecords(out, cfg, recs, errs)
+	closeOut()
+
+	if cfg.OpenFailing {
+		openFailing(recs)
+	}
+
+	pushSummary(cfg, recs)
 
-	printTable(cfg, prs)
+	reportEnrichErrors(cfg, errs)
 }
 
-func getConfig() config {
+// printRecords writes recs in the --output format.
+func printRecords(out io.Writer, cfg config, recs []prRecord, errs *enrichErrors) {
+	switch {
+	case cfg.Output == outputMarkdown:
+		printMarkdown(out, cfg, recs)
+	case cfg.Output == outputCSV:
+		if err := printCSV(out, cfg, recs); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case cfg.Output == outputJSON:
+		if err := printJSON(out, recs); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case cfg.Output == outputTemplate:
+		if err := printTemplate(out, cfg.Template, recs); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case cfg.Output == outputNDJSON || cfg.Output == outputNDJSONWithErrors:
+		if err := printNDJSON(out, recs, errs, cfg.Output == outputNDJSONWithErrors); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case cfg.CountByStatus:
+		fmt.Fprintln(out, countByStatusLine(summarize(recs, time.Now())))
+	case cfg.StatsByAuthor:
+		printAuthorStats(out, cfg, recs)
+	case cfg.Oneline:
+		printOneline(out, cfg, recs)
+	default:
+		printTable(out, cfg, recs)
+	}
+}
+
+// fetchSelected fetches the PRs and applies the filters that need no per-PR calls,
+// including --my-work. me is the resolved user under --my-work, zero otherwise.
+func fetchSelected(cfg config) (prs []pullRequest, me userIdentity, err error) {
+	if cfg.multiOrg() {
+		return fetchAllOrgs(cfg)
+	}
+	projects, err := selectedProjects(cfg)
+	if err != nil {
+		return nil, me, err
+	}
+	for _, p := range projects {
+		pcfg := cfg
+		pcfg.Project = p
+		var pprs []pullRequest
+		switch {
+		case cfg.Since == sinceLast:
+			pprs, err = fetchIncremental(pcfg)
+		case !cfg.SinceTime.IsZero():
+			pprs, err = fetchCreatedSince(pcfg, cfg.SinceTime)
+		default:
+			pprs, err = fetchActivePRs(pcfg)
+		}
+		if err != nil {
+			if len(projects) > 1 {
+				return nil, me, fmt.Errorf("project %s: %w", p, err)
+			}
+			return nil, me, err
+		}
+		prs = append(prs, pprs...)
+	}
+
+	prs = filterPRs(cfg, prs)
+
+	if cfg.MyWork || cfg.MineToReview || cfg.Mine {
+		me, err = resolveMe(cfg)
+		if err != nil {
+			return nil, me, fmt.Errorf("resolving your identity: %w", err)
+		}
+	}
+	if cfg.MyWork {
+		prs = filterMyWork(prs, me)
+	}
+	if cfg.MineToReview {
+		prs = filterToReview(prs, me)
+	}
+	if cfg.Mine {
+		prs = filterMine(prs, me)
+	}
+	return prs, me, nil
+}
+
+// finishRecords enriches prs, applies the filters that need enrichment data, sets
+// the --my-work roles and sorts the result.
+func finishRecords(cfg config, prs []pullRequest, me userIdentity, errs *enrichErrors) []prRecord {
+	recs := enrichPRs(cfg, prs, errs)
+	recs = filterRecords(cfg, recs)
+	if cfg.MyWork {
+		for i := range recs {
+			recs[i].Role = roleOf(recs[i].pullRequest, me)
+		}
+	}
+	sortRecords(cfg.Sort, recs)
+	return recs
+}
+
+// pushSummary pushes the metrics of recs to --pushgateway, if set.
+func pushSummary(cfg config, recs []prRecord) {
+	if cfg.Pushgateway == "" {
+		return
+	}
+	if err := pushMetrics(cfg, summarize(recs, time.Now())); err != nil {
+		if cfg.Strict {
+			log.Fatalln("Error: pushing metrics:", err)
+		}
+		fmt.Fprintln(os.Stderr, "Warning: pushing metrics:", err)
+	}
+}
+
+// noResults prints msg, or exits with exitNoResults under --fail-if-none. Other
+// outputs than the table print an empty result instead, e.g. [] for json, a header-only
+// CSV or all-zero counts with --count-by-status.
+func noResults(cfg config, msg string) {
+	// an empty queue must reset the gauges, or the gateway keeps serving the last ones
+	pushSummary(cfg, nil)
+	if cfg.FailIfNone {
+		fmt.Fprintln(os.Stderr, "Error:", msg)
+		os.Exit(exitNoResults)
+	}
+	if cfg.CountByStatus || cfg.Output != outputTable {
+		// formats read by scripts get an empty result rather than prose
+		out, closeOut := openOutput(cfg)
+		printRecords(out, cfg, []prRecord{}, newEnrichErrors())
+		closeOut()
+		return
+	}
+	fmt.Println(msg)
+}
+
+// reportEnrichErrors prints collected enrichment failures under --debug/--strict
+// and exits with exitEnrichmentFailed under --strict.
+func reportEnrichErrors(cfg config, errs *enrichErrors) {
+	if errs.len() > 0 && (cfg.Debug || cfg.Strict) {
+		errs.print(os.Stderr)
+	}
+	if errs.len() > 0 && cfg.Strict {
+		os.Exit(exitEnrichmentFailed)
+	}
+}
+
+// getConfig parses args (the flags and positional arguments following the command)
+// together with the config file and environment into a config.
+func getConfig(args []string) config {
 	// Flags
-	org := flag.String("org", "", "Azure DevOps organization (e.g., myorg)")
-	project := flag.String("project", "", "Azure DevOps project name")
-	top := flag.Int("top", 50, "Max number of PRs to fetch")
+	var orgs stringList
+	flag.Var(&orgs, "org", "Azure DevOps organization (e.g., myorg); repeat or comma-separate to list the PRs of several organizations")
+	baseURL := flag.String("base-url", "", "Azure DevOps Server collection URL, e.g. https://tfs.corp.local/tfs/DefaultCollection (default: Azure DevOps Services)")
+	var projects stringList
+	flag.Var(&projects, "project", "Azure DevOps project name; repeat or comma-separate for several, or * for every project of the organization")
+	top := flag.Int("top", 50, "Max number of PRs to fetch; 0 fetches every matching PR, page by page")
+	all := flag.Bool("all", false, "Fetch every matching PR, page by page (same as --top 0)")
+	pageSize := flag.Int("page-size", 100, fmt.Sprintf("Number of PRs requested per API call (1-%d)", maxPageSize))
 	apiVer := flag.String("api-version", "7.1-preview.1", "Azure DevOps API version")
-	flag.Parse()
+	utc := flag.Bool("utc", false, "Render timestamps in UTC instead of local time")
+	source := flag.String("source", "", "Only show PRs from this source ref (branch, tags/X, or full ref); globs like feature/* are allowed")
+	target := flag.String("target", "", "Only show PRs into this target ref (branch, tags/X, or full ref); globs like release/* are allowed")
+	debug := flag.Bool("debug", false, "Print diagnostic output, including a summary of failed enrichment calls")
+	strict := flag.Bool("strict", false, fmt.Sprintf("Report failed enrichment calls and exit with code %d if there were any", exitEnrichmentFailed))
+	olderThan := flag.String("older-than", "", "Only show PRs created longer ago than this age (e.g. 36h, 7d, 2w)")
+	status := flag.String("status", prStatusActive, "Which PRs to list: active, completed, abandoned or all")
+	since := flag.String("since", "", "Only fetch PRs created since a time (RFC3339, YYYY-MM-DD or age like 36h/7d), or 'last' for incremental mode")
+	pushgateway := flag.String("pushgateway", "", "Push PR queue gauges to this Prometheus Pushgateway URL")
+	pushJob := flag.String("pushgateway-job", "lazydevops", "Job label for --pushgateway")
+	pushInstance := flag.String("pushgateway-instance", "", "Instance label for --pushgateway (defaults to <org>/<project>)")
+	tlsMin := flag.String("tls-min-version", "1.2", "Minimum TLS version to accept: 1.2 or 1.3")
+	var tlsCiphers stringList
+	flag.Var(&tlsCiphers, "tls-ciphers", "Restrict TLS 1.2 cipher suites (Go names, repeatable or comma-separated)")
+	concurrency := flag.Int("concurrency", 8, "Number of concurrent per-PR API calls to start with")
+	minConcurrency := flag.Int("min-concurrency", 1, "Lowest concurrency to back off to when throttled")
+	maxConcurrency := flag.Int("max-concurrency", 32, "Highest concurrency to ramp up to while not throttled")
+	var repos stringList
+	flag.Var(&repos, "repo", "Only show PRs of this repository; repeat or comma-separate for several")
+	var workItems stringList
+	flag.Var(&workItems, "only-with-work-item", "Only show PRs linked to this work item ID (repeatable or comma-separated; any match)")
+	showWorkItems := flag.Bool("show-work-items", false, "Show a WIs column with the work items linked to each PR")
+	requireWorkItem := flag.Bool("require-work-item", false, "Show the WIs column and highlight PRs without a linked work item")
+	myWork := flag.Bool("my-work", false, "Only show PRs you created or still need to review, with a Role column")
+	mineToReview := flag.Bool("mine-to-review", false, "Only show PRs where you are a reviewer and have not voted yet")
+	mine := flag.Bool("mine", false, "Only show PRs you created")
+	var authors stringList
+	flag.Var(&authors, "author", "Only show PRs created by this user: email, or part of the display name (repeatable or comma-separated; any match)")
+	var identityAliases stringList
+	flag.Var(&identityAliases, "identity-alias", "Another unique name (email, UPN, DOMAIN\\user) that is also you, for --my-work, --mine and --mine-to-review; repeatable or comma-separated")
+	groupBy := flag.String("group-by", "", "Group table rows into sections: repo, author, target-branch, or role (requires --my-work)")
+	groupSort := flag.String("group-sort", groupSortCount, "Order repo, author and target-branch groups by count, age (oldest PR) or failing (PRs with failed checks), descending")
+	showGap := flag.Bool("reviewers-required-count", false, "Show a Gap column with the approvals still required by branch policy")
+	gapOnly := flag.Bool("approval-gap-only", false, "Only show PRs that still need approvals to satisfy branch policy")
+	sortSpec := flag.String("sort", "created:desc", "Comma-separated sort keys with optional :asc/:desc ("+strings.Join(sortKeyNames(), ", ")+")")
+	reverse := flag.Bool("reverse", false, "Reverse the direction of every --sort key")
+	output := flag.String("output", outputTable, "Output format: table, markdown, csv, json (one array of PRs), ndjson (one JSON object per PR), ndjson-with-errors (also records failed enrichment calls) or template (--template per PR)")
+	format := flag.String("format", "", "Alias of --output")
+	var columns stringList
+	flag.Var(&columns, "columns", "Table columns in order, comma-separated ("+strings.Join(columnNames(), ", ")+")")
+	wide := flag.Bool("wide", false, "Add description, comment count and label columns to the table")
+	templateText := flag.String("template", "", "Go text/template printed per PR with --output template, e.g. '{{.PullRequestID}} {{.Title}}'")
+	titleWidth := flag.Int("title-width", 0, "Wrap titles at this many display columns (0 disables wrapping)")
+	maxTitleLines := flag.Int("max-title-lines", 0, "Keep at most this many wrapped title lines (0 for all)")
+	colorTheme := flag.String("color-theme", "dark", "Table color theme: "+strings.Join(themeNames(), ", "))
+	_, noColorEnv := os.LookupEnv("NO_COLOR")
+	noColor := flag.Bool("no-color", noColorEnv, "Disable colors (also set by the NO_COLOR environment variable)")
+	statsByAuthor := flag.Bool("stats-by-author", false, "Print per-author open PR counts, average age, failing checks and waiting-for-author votes instead of the PR list")
+	jsonOut := flag.String("json-out", "", "Also write the enriched records as JSON to this file, whatever the --output format")
+	outFile := flag.String("out", "", "Write the output to this file instead of stdout (without colors)")
+	displayLimit := flag.Int("display-limit", 0, "Show only the first N PRs after sorting; the summary still counts all of them (0 = all)")
+	stream := flag.Bool("stream", false, "Print PRs in --oneline form as soon as their checks are fetched (completion order, unsorted)")
+	oneline := flag.Bool("oneline", false, "Print one compact line per PR instead of a table")
+	showDescription := flag.Bool("show-description", false, "Print a one-line, truncated PR description under each row")
+	nonDefaultTarget := flag.Bool("non-default-target-only", false, "Only show PRs that do not target their repository's default branch")
+	conflictsOnly := flag.Bool("conflicts-only", false, "Only show PRs that cannot be merged because of conflicts")
+	draftsOnly := flag.Bool("drafts", false, "Only show draft PRs")
+	noDrafts := flag.Bool("no-drafts", false, "Leave out draft PRs")
+	noReviewers := flag.Bool("no-reviewers", false, "Only show PRs with no reviewers assigned")
+	countByStatus := flag.Bool("count-by-status", false, "Print a single line of counts per check status (active=N failing=N ...) for alerting scripts")
+	benchmark := flag.Bool("benchmark", false, "Fetch check statuses at several concurrency levels, report the fastest and exit")
+	tui := flag.Bool("tui", false, "Browse the PRs in an interactive terminal UI with a detail pane (r refresh, o open, q quit)")
+	var watch watchFlag
+	flag.Var(&watch, "watch", "Refresh the list on a timer, marking changed PRs; --watch alone refreshes every minute, --watch=30s sets the interval")
+	notifyFlag := flag.Bool("notify", false, "With --watch, send desktop notifications for new PRs and for votes and failed checks on your PRs")
+	staleCheckAge := flag.String("stale-check-age", "2h", "Show pending checks not updated for this long as \"Stuck?\" (e.g. 90m, 1d; 0 disables)")
+	openFailingFlag := flag.Bool("open-failing", false, "Open every PR whose checks failed in the browser")
+	initFlag := flag.Bool("init", false, "Interactively create the config file (org, project, PAT)")
+	failIfNone := flag.Bool("fail-if-none", false, fmt.Sprintf("Exit with code %d when no pull requests match", exitNoResults))
+	preset := flag.String("preset", "", "Apply a named set of flags from the config file's presets section")
+	listPresets := flag.Bool("list-presets", false, "List the presets defined in the config file and exit")
+	explainConfig := flag.Bool("explain-config", false, "Print every resolved setting and where it came from (flag, env, file, preset, default), then exit")
+	profile := flag.String("profile", os.Getenv(envVarProfile), "Use the named profile of the config file (org, project, PAT); comma-separate several to list the PRs of all of them; defaults to $"+envVarProfile)
+	noDetect := flag.Bool("no-detect", false, "Do not take org, project and repo from the git remote of the current directory")
+	auth := flag.String("auth", authPAT, "Authentication: pat (Personal Access Token) or azcli (Azure CLI login, no PAT needed)")
+	positional, err := parseInterspersed(flag.CommandLine, args)
+	if err != nil {
+		failUsage(err.Error())
+	}
+	src := newConfigSources(flag.CommandLine)
+	org := new(string)
+	if len(orgs) > 0 {
+		*org = orgs[0]
+	}
+	var profiles []string
+	for _, p := range strings.Split(*profile, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			profiles = append(profiles, p)
+		}
+	}
 
-	pat := os.Getenv(envVarPrimaryPAT)
+	rawFC, err := loadFileConfig()
+	if err != nil {
+		failUsage(err.Error())
+	}
+	fc, fileSrc := rawFC, sourceFile
+	if len(profiles) > 0 {
+		if fc, err = rawFC.withProfile(profiles[0]); err != nil {
+			failUsage("--profile: " + err.Error())
+		}
+		fileSrc = "file, profile " + profiles[0]
+		if src["profile"] == sourceDefault {
+			src["profile"] = "env " + envVarProfile
+		}
+	}
+	if *listPresets {
+		printPresets(fc)
+		os.Exit(0)
+	}
+	if *preset != "" {
+		applied, err := applyPreset(flag.CommandLine, fc, *preset)
+		if err != nil {
+			failUsage("--preset: " + err.Error())
+		}
+		for _, k := range applied {
+			src[k] = "preset " + *preset
+		}
+	}
+	if noColorEnv && src["no-color"] == sourceDefault {
+		src["no-color"] = "env NO_COLOR"
+	}
+	if *baseURL == "" && fc.BaseURL != "" {
+		*baseURL = fc.BaseURL
+		src["base-url"] = fileSrc
+	}
+	if *baseURL != "" {
+		collection, err := collectionName(*baseURL)
+		if err != nil {
+			failUsage("--base-url: " + err.Error())
+		}
+		if *org == "" && fc.Org == "" {
+			// The collection plays the part of the organization.
+			*org = collection
+			src["org"] = "base-url"
+		}
+	}
+	if !*noDetect && *profile == "" && (*org == "" || len(projects) == 0) {
+		// Inside a checkout of an Azure DevOps repository, default to that repository.
+		if remote, err := originRemote(*baseURL); err == nil && (*org == "" || strings.EqualFold(*org, remote.Org)) {
+			if *org == "" {
+				*org = remote.Org
+				src["org"] = sourceGitRemote
+			}
+			if len(projects) == 0 {
+				projects = stringList{remote.Project}
+				src["project"] = sourceGitRemote
+				if len(repos) == 0 {
+					repos = stringList{remote.Repo}
+					src["repo"] = sourceGitRemote
+				}
+			}
+		}
+	}
+	if *org == "" && fc.Org != "" {
+		*org = fc.Org
+		src["org"] = fileSrc
+	}
+	if len(projects) == 0 && fc.Project != "" {
+		projects = stringList{fc.Project}
+		src["project"] = fileSrc
+	}
+	if src["api-version"] == sourceDefault {
+		if v, from := fc.apiVersionFor(*org); v != "" {
+			*apiVer = v
+			src["api-version"] = from
+		}
+	}
+	if *all {
+		*top = 0
+	} else if src["top"] == sourceDefault && fc.Top > 0 {
+		*top = fc.Top
+		src["top"] = sourceFile
+	}
+	if src["output"] == sourceDefault && fc.Output != "" {
+		*output = fc.Output
+		src["output"] = sourceFile
+	}
+	pat, patEnv, patSrc := resolvePAT(fc, *auth, *org, fileSrc)
+	src[settingPAT] = patSrc
+	if len(orgs) == 0 && *org != "" {
+		orgs = stringList{*org}
+	}
+	if *explainConfig {
+		printConfigExplanation(os.Stdout, flag.CommandLine, src, pat)
+		os.Exit(0)
+	}
 
-	if *org == "" || *project == "" {
-		failUsage("--org and --project are required. Set " + envVarPrimaryPAT + " env var for authentication.")
+	flagProjects := projects
+	if src["project"] == fileSrc || src["project"] == sourceGitRemote {
+		flagProjects = nil
 	}
-	if pat == "" {
-		failUsage("Environment variable " + envVarPrimaryPAT + " is required for authentication.")
+	conns, err := orgConnections(rawFC, orgs, flagProjects, profiles, *auth, *apiVer, src["api-version"] != sourceDefault, *baseURL)
+	if err != nil {
+		failUsage(err.Error())
+	}
+
+	if !*initFlag {
+		if len(conns) > 1 {
+			for _, c := range conns {
+				if c.Pat == "" && strings.ToLower(*auth) == authPAT {
+					failUsage("no PAT for organization " + c.Org + " (set its profile's pat-env, or run auth login --org " + c.Org + ")")
+				}
+			}
+		}
+		if *org == "" || len(projects) == 0 {
+			failUsage("--org and --project are required (or run --init). Set " + envVarPrimaryPAT + " env var for authentication.")
+		}
+		switch strings.ToLower(*auth) {
+		case authPAT:
+			if pat == "" {
+				failUsage("Environment variable " + patEnv + " is required for authentication (or use --auth azcli).")
+			}
+		case authAzCLI:
+		default:
+			failUsage("--auth must be pat or azcli")
+		}
 	}
 
 	cfg := config{
-		Org:     *org,
-		Project: *project,
-		Pat:     pat,
-		Top:     *top,
-		ApiVer:  *apiVer,
+		Init:                 *initFlag,
+		Auth:                 strings.ToLower(*auth),
+		File:                 rawFC,
+		Org:                  *org,
+		BaseURL:              strings.TrimSuffix(*baseURL, "/"),
+		Projects:             projects,
+		Orgs:                 conns,
+		Pat:                  pat,
+		Top:                  *top,
+		PageSize:             *pageSize,
+		ApiVer:               *apiVer,
+		UTC:                  *utc,
+		FailIfNone:           *failIfNone,
+		Source:               *source,
+		Target:               *target,
+		Debug:                *debug,
+		Strict:               *strict,
+		OpenFailing:          *openFailingFlag,
+		Since:                strings.TrimSpace(*since),
+		Status:               strings.ToLower(strings.TrimSpace(*status)),
+		NoReviewers:          *noReviewers,
+		DraftsOnly:           *draftsOnly,
+		ConflictsOnly:        *conflictsOnly,
+		NoDrafts:             *noDrafts,
+		NonDefaultTargetOnly: *nonDefaultTarget,
+
+		ShowDescription: *showDescription,
+		Pushgateway:     *pushgateway,
+		PushJob:         *pushJob,
+		PushInstance:    *pushInstance,
+		Oneline:         *oneline,
+		Stream:          *stream,
+		StatsByAuthor:   *statsByAuthor,
+		CountByStatus:   *countByStatus,
+		Benchmark:       *benchmark,
+		TUI:             *tui,
+		Watch:           watch,
+		Notify:          *notifyFlag,
+		ColorTheme:      strings.ToLower(strings.TrimSpace(*colorTheme)),
+		NoColor:         *noColor || *outFile != "",
+		TitleWidth:      *titleWidth,
+		MaxTitleLines:   *maxTitleLines,
+		Output:          strings.ToLower(strings.TrimSpace(*output)),
+		JSONOut:         *jsonOut,
+		OutFile:         *outFile,
+		DisplayLimit:    *displayLimit,
+		MyWork:          *myWork,
+		MineToReview:    *mineToReview,
+		Mine:            *mine,
+		Authors:         authors,
+		IdentityAliases: identityAliases,
+		GroupBy:         strings.ToLower(strings.TrimSpace(*groupBy)),
+		GroupSort:       strings.ToLower(strings.TrimSpace(*groupSort)),
+		ShowGap:         *showGap || *gapOnly,
+		GapOnly:         *gapOnly,
+		WorkItems:       workItems,
+		ShowWorkItems:   *showWorkItems || *requireWorkItem,
+		RequireWorkItem: *requireWorkItem,
+		Repos:           repos,
+
+		Concurrency:    *concurrency,
+		MinConcurrency: *minConcurrency,
+		MaxConcurrency: *maxConcurrency,
+	}
+	if cfg.Sort, err = parseSortKeys(*sortSpec, *reverse); err != nil {
+		failUsage("--sort: " + err.Error())
+	}
+	if cfg.DisplayLimit < 0 {
+		failUsage("--display-limit must be 0 or more")
+	}
+	if cfg.PageSize < 1 || cfg.PageSize > maxPageSize {
+		failUsage(fmt.Sprintf("--page-size must be between 1 and %d", maxPageSize))
+	}
+	if cfg.MinConcurrency < 1 || cfg.MinConcurrency > cfg.Concurrency || cfg.Concurrency > cfg.MaxConcurrency {
+		failUsage("concurrency flags must satisfy 1 <= --min-concurrency <= --concurrency <= --max-concurrency")
+	}
+	if len(cfg.Projects) > 0 {
+		cfg.Project = cfg.Projects[0]
+	}
+	if len(cfg.Projects) > 1 && slices.Contains(cfg.Projects, allProjects) {
+		failUsage("--project " + allProjects + " cannot be combined with other projects")
+	}
+	if cfg.PushInstance == "" {
+		cfg.PushInstance = cfg.Org + "/" + strings.Join(cfg.Projects, ",")
+	}
+	if cfg.Notify && cfg.Watch == 0 {
+		failUsage("--notify requires --watch")
+	}
+	switch cfg.GroupBy {
+	case "", groupByRepo, groupByAuthor, groupByTarget:
+	case groupByRole:
+		if !cfg.MyWork {
+			failUsage("--group-by role requires --my-work")
+		}
+	default:
+		failUsage("--group-by must be one of: " + strings.Join([]string{groupByRepo, groupByAuthor, groupByTarget, groupByRole}, ", "))
+	}
+	if cfg.TitleWidth < 0 || cfg.MaxTitleLines < 0 || (cfg.TitleWidth > 0 && cfg.TitleWidth < 2) {
+		failUsage("--title-width must be 0 or at least 2, and --max-title-lines must not be negative")
+	}
+	if cfg.MaxTitleLines > 0 && cfg.TitleWidth == 0 {
+		failUsage("--max-title-lines requires --title-width")
+	}
+	if !validTheme(cfg.ColorTheme) {
+		failUsage("--color-theme must be one of: " + strings.Join(themeNames(), ", "))
+	}
+	if f := strings.ToLower(strings.TrimSpace(*format)); f != "" {
+		if src["output"] == sourceFlag && f != cfg.Output {
+			failUsage("--format and --output disagree; use one of them")
+		}
+		cfg.Output = f
+	}
+	switch cfg.Output {
+	case outputTable, outputJSON, outputNDJSON, outputNDJSONWithErrors, outputMarkdown, outputCSV, outputTemplate:
+	default:
+		failUsage("--output must be one of: table, json, ndjson, ndjson-with-errors, markdown, csv, template")
+	}
+	if cfg.Watch > 0 && cfg.Output != outputTable && cfg.Output != outputTemplate {
+		failUsage("--watch redraws the terminal and only works with the table, --oneline and --output template")
+	}
+	if cfg.Watch > 0 && cfg.OutFile != "" {
+		failUsage("--watch redraws the terminal and cannot be combined with --out")
+	}
+	if cfg.Columns, err = parseColumns(columns); err != nil {
+		failUsage("--columns: " + err.Error())
+	}
+	cfg.Wide = *wide
+	if slices.Contains(cfg.Columns, "gap") {
+		cfg.ShowGap = true
+	}
+	if slices.Contains(cfg.Columns, "wis") {
+		cfg.ShowWorkItems = true
+	}
+	switch {
+	case cfg.Output == outputTemplate && *templateText == "":
+		failUsage("--output template needs --template")
+	case cfg.Output != outputTemplate && *templateText != "":
+		failUsage("--template needs --output template")
+	case *templateText != "":
+		tmpl, err := parseOutputTemplate(*templateText)
+		if err != nil {
+			failUsage("--template: " + err.Error())
+		}
+		cfg.Template = tmpl
+	}
+	switch cfg.GroupSort {
+	case groupSortCount, groupSortAge, groupSortFailing:
+	default:
+		failUsage("--group-sort must be one of: count, age, failing")
+	}
+	v, ok := tlsVersions[*tlsMin]
+	if !ok {
+		failUsage("--tls-min-version must be 1.2 or 1.3")
+	}
+	cfg.TLSMinVersion = v
+	suites, err := parseCipherSuites(tlsCiphers)
+	if err != nil {
+		failUsage("--tls-ciphers: " + err.Error())
+	}
+	cfg.TLSCipherSuites = suites
+	cfg.Args = positional
+	for _, id := range cfg.WorkItems {
+		if _, err := strconv.Atoi(id); err != nil {
+			failUsage("--only-with-work-item expects numeric work item IDs, got " + id)
+		}
+	}
+	if cfg.DraftsOnly && cfg.NoDrafts {
+		failUsage("--drafts and --no-drafts are mutually exclusive")
+	}
+	switch cfg.Status {
+	case prStatusActive, prStatusCompleted, prStatusAbandoned, prStatusAll:
+	default:
+		failUsage("--status must be one of: active, completed, abandoned, all")
+	}
+	if cfg.Since == sinceLast && cfg.Status != prStatusActive {
+		failUsage("--since last only works with --status active")
+	}
+	if cfg.Since != "" && cfg.Since != sinceLast {
+		t, err := parseSince(cfg.Since, time.Now())
+		if err != nil {
+			failUsage("--since: " + err.Error())
+		}
+		cfg.SinceTime = t
+	}
+	if s := strings.TrimSpace(*olderThan); s != "" {
+		if cfg.OlderThan, err = parseAge(s); err != nil {
+			failUsage("--older-than: " + err.Error())
+		}
+	}
+	if cfg.StaleCheckAge, err = parseAge(strings.TrimSpace(*staleCheckAge)); err != nil {
+		failUsage("--stale-check-age: " + err.Error())
+	}
+	if cfg.Debug {
+		debugLog.SetOutput(os.Stderr)
 	}
 	return cfg
 }
 
-func fetchActivePRs(cfg config) ([]pullRequest, error) {
-	base := fmt.Sprintf("https://dev.azure.com/%s/%s/_apis/git/pullrequests", url.PathEscape(cfg.Org), url.PathEscape(cfg.Project))
-	q := url.Values{}
-	q.Set("searchCriteria.status", "active")
-	if cfg.Top > 0 {
-		q.Set("$top", fmt.Sprintf("%d", cfg.Top))
-	}
-	q.Set("api-version", cfg.ApiVer)
+// --status values, which are also the API's searchCriteria.status values.
+const (
+	prStatusActive    = "active"
+	prStatusCompleted = "completed"
+	prStatusAbandoned = "abandoned"
+	prStatusAll       = "all"
+)
 
-	endpoint := base + "?" + q.Encode()
+// statusLabel describes the PRs a --status selects, for messages.
+func statusLabel(status string) string {
+	if status == prStatusAll {
+		return "active, completed or abandoned"
+	}
+	return status
+}
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+// fetchActivePRs lists the PRs of the --status, active ones by default.
+func fetchActivePRs(cfg config) ([]pullRequest, error) {
+	q, err := serverCriteria(cfg)
 	if err != nil {
 		return nil, err
 	}
+	return fetchPRs(cfg, q)
+}
 
-	// PAT via basic auth (username can be empty or anything)
-	token := base64.StdEncoding.EncodeToString([]byte(":" + cfg.Pat))
-	req.Header.Set("Authorization", "Basic "+token)
-	req.Header.Set("Accept", "application/json")
+// serverCriteria returns the search criteria for the --status and the filters the
+// server can apply itself; the rest are applied by filterPRs.
+func serverCriteria(cfg config) (url.Values, error) {
+	q := url.Values{}
+	q.Set("searchCriteria.status", cfg.Status)
+	if len(cfg.Repos) == 1 && !cfg.multiProject() {
+		// A single repository can be filtered by the server; several, or a repository
+		// of one of several projects, are filtered by filterPRs.
+		repo, err := (&repoIndex{}).lookup(cfg, cfg.Repos[0])
+		if err != nil {
+			return nil, fmt.Errorf("--repo: %w", err)
+		}
+		q.Set("searchCriteria.repositoryId", repo.ID)
+	}
+	if t := strings.TrimSpace(cfg.Target); t != "" && !isRefGlob(t) {
+		// An exact target is filtered by the server as well, so other PRs do not count against --top.
+		ref := t
+		if strings.HasPrefix(t, "tags/") {
+			ref = "refs/" + t
+		}
+		q.Set("searchCriteria.targetRefName", branchRef(ref))
+	}
+	return q, nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+// fetchPRs lists up to cfg.Top pull requests in the project matching the given search
+// criteria, requesting them in pages of cfg.PageSize. A Top of 0 or less pages through
+// all of them.
+func fetchPRs(cfg config, criteria url.Values) ([]pullRequest, error) {
+	var all []pullRequest
+	lastPageFull := false
+	for page := 1; cfg.Top <= 0 || len(all) < cfg.Top; page++ {
+		size := cfg.PageSize
+		if cfg.Top > 0 {
+			size = min(size, cfg.Top-len(all))
+		}
+		prs, err := fetchPRPage(cfg, criteria, len(all), size)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, prs...)
+		debugLog.Printf("fetched page %d: %d PRs (%d total)", page, len(prs), len(all))
+		lastPageFull = len(prs) == size
+		if !lastPageFull {
+			break
+		}
+	}
+	if lastPageFull && cfg.Top > 0 {
+		// the server had at least as many PRs as we asked for, so there may be more
+		fmt.Fprintln(os.Stderr, "Warning:", msgN(msgTruncated, cfg.Top))
 	}
-	defer resp.Body.Close()
+	return all, nil
+}
+
+// fetchPRPage requests a single page of pull requests; top <= 0 leaves the page size to the server.
+// Responses cut short by the network are retried.
+func fetchPRPage(cfg config, criteria url.Values, skip, top int) ([]pullRequest, error) {
+	for attempt := 0; ; attempt++ {
+		prs, err := fetchPRPageOnce(cfg, criteria, skip, top)
+		if !isTruncated(err) || attempt >= maxTruncatedRetries {
+			return prs, err
+		}
+		debugLog.Printf("response truncated (%v), retrying", err)
+		time.Sleep(truncatedRetryDelay << attempt)
+	}
+}
 
-	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+func fetchPRPageOnce(cfg config, criteria url.Values, skip, top int) ([]pullRequest, error) {
+	prs, err := apiClient(cfg, 0).PullRequests(context.Background(), cfg.Project, criteria, skip, top)
+	var se *httpStatusError
+	switch {
+	case errors.As(err, &se) && (se.Code == http.StatusUnauthorized || se.Code == http.StatusForbidden):
 		return nil, errors.New("authentication failed (401/403). Ensure " + envVarPrimaryPAT + " is valid and has Code (Read) scope")
+	case se != nil:
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("request failed: %s", resp.Status)
+	return prs, err
+}
+
+// enrichPRs runs the per-PR API calls (check status) concurrently, bounded by an adaptive
+// limiter, and returns the records in input order.
+func enrichPRs(cfg config, prs []pullRequest, errs *enrichErrors) []prRecord {
+	recs := make([]prRecord, len(prs))
+	for res := range enrichAsync(cfg, prs, errs) {
+		recs[res.Index] = res.Rec
+	}
+	return recs
+}
+
+// enrichResult is a finished record together with its position in the input.
+type enrichResult struct {
+	Index int
+	Rec   prRecord
+}
+
+// enrichAsync enriches prs concurrently and delivers each record as soon as it is
+// finished, in completion order. The channel is closed once all PRs are done.
+func enrichAsync(cfg config, prs []pullRequest, errs *enrichErrors) <-chan enrichResult {
+	e := &enricher{
+		cfg:   cfg,
+		lim:   newAdaptiveLimiter(cfg.Concurrency, cfg.MinConcurrency, cfg.MaxConcurrency),
+		errs:  errs,
+		repos: &projectRepos{},
+	}
+	out := make(chan enrichResult)
+	var wg sync.WaitGroup
+	for i, pr := range prs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out <- enrichResult{Index: i, Rec: e.enrich(pr)}
+		}()
 	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// enricher holds the state shared by the concurrent per-PR enrichment calls.
+type enricher struct {
+	cfg   config
+	lim   *adaptiveLimiter
+	errs  *enrichErrors
+	repos *projectRepos
+}
 
-	var prr prResponse
-	dec := json.NewDecoder(resp.Body)
-	dec.DisallowUnknownFields()
-	if err := dec.Decode(&prr); err != nil {
-		// if strict decode fails due to preview changes, retry with permissive decode
-		resp.Body.Close()
-		// Re-do request to get a fresh body
-		req2, _ := http.NewRequest("GET", endpoint, nil)
-		req2.Header = req.Header.Clone()
-		resp2, e2 := client.Do(req2)
-		if e2 != nil {
-			return nil, e2
+func (e *enricher) enrich(pr pullRequest) prRecord {
+	cfg, lim, errs := e.cfg.forOrg(pr.Org), e.lim, e.errs
+	cfg.Project = prProject(cfg, pr)
+	rec := prRecord{pullRequest: pr}
+	if pr.Repository.ID == "" {
+		// Some listings omit the repository ID; per-PR endpoints need it.
+		repo, err := e.repos.of(cfg).lookup(cfg, pr.Repository.Name)
+		if err != nil {
+			debugLog.Printf("PR %d: no repository ID and %q could not be resolved (%v); skipping per-PR calls", pr.PullRequestID, pr.Repository.Name, err)
+			rec.Checks = "N/A"
+			return rec
+		}
+		pr.Repository.ID = repo.ID
+		rec.Repository.ID = repo.ID
+	}
+	if cfg.needsDefaultBranch() {
+		if repo, err := e.repos.of(cfg).lookup(cfg, pr.Repository.Name); err == nil {
+			rec.DefaultBranch = repo.DefaultBranch
+		} else {
+			debugLog.Printf("PR %d: default branch unknown: %v", pr.PullRequestID, err)
 		}
-		defer resp2.Body.Close()
-		if resp2.StatusCode < 200 || resp2.StatusCode >= 300 {
-			return nil, fmt.Errorf("request failed: %s", resp2.Status)
+	}
+	err := lim.do(func(ctx context.Context) error {
+		var err error
+		rec.Checks, err = getPRStatusOverall(ctx, cfg, pr)
+		return err
+	})
+	if err != nil {
+		errs.add(pr, enrichStatus, err)
+	}
+	if pr.IsDraft {
+		rec.ReadyToPublish = rec.Checks == "Passed"
+	}
+	if cfg.ShowGap {
+		err := lim.do(func(ctx context.Context) error {
+			evals, err := fetchPolicyEvaluations(ctx, cfg, pr)
+			if err == nil {
+				rec.ApprovalGap = approvalGap(pr, evals)
+			}
+			return err
+		})
+		if err != nil {
+			errs.add(pr, enrichPolicies, err)
 		}
-		type loose struct {
-			Value []pullRequest `json:"value"`
+	}
+	if cfg.needsThreads() {
+		err := lim.do(func(ctx context.Context) error {
+			threads, err := fetchAllThreads(ctx, cfg, pr)
+			if err == nil {
+				rec.Updated = lastActivity(pr, threads)
+				rec.Comments = &threadCounts{}
+				for _, t := range commentThreads(threads) {
+					if t.isActive() {
+						rec.Comments.Active++
+					} else {
+						rec.Comments.Resolved++
+					}
+				}
+			}
+			return err
+		})
+		if err != nil {
+			errs.add(pr, enrichThreads, err)
 		}
-		var lr loose
-		if e := json.NewDecoder(resp2.Body).Decode(&lr); e != nil {
-			return nil, e
+	}
+	if len(cfg.WorkItems) > 0 || cfg.ShowWorkItems {
+		err := lim.do(func(ctx context.Context) error {
+			var err error
+			rec.WorkItems, err = fetchPRWorkItems(ctx, cfg, pr)
+			return err
+		})
+		if err != nil {
+			errs.add(pr, enrichWorkItems, err)
 		}
-		return lr.Value, nil
 	}
+	return rec
+}
 
-	return prr.Value, nil
+// filterRecords applies the filters that depend on enrichment results, preserving order.
+func filterRecords(cfg config, recs []prRecord) []prRecord {
+	out := recs[:0]
+	for _, r := range recs {
+		if len(cfg.WorkItems) > 0 && !anyIn(r.WorkItems, cfg.WorkItems) {
+			continue
+		}
+		if cfg.GapOnly && (r.ApprovalGap == nil || *r.ApprovalGap == 0) {
+			continue
+		}
+		if cfg.NonDefaultTargetOnly && !r.nonDefaultTarget() {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
 }
 
-func printTable(cfg config, prs []pullRequest) {
+func printTable(out io.Writer, cfg config, recs []prRecord) {
 	w := table.NewWriter()
-	w.SetOutputMirror(os.Stdout)
-	w.SetStyle(table.StyleColoredDark)
-	w.AppendHeader(table.Row{"PR", "Title", "Author", "Repo", "Source->Target", "Votes", "Checks", "Created", "URL"})
+	w.SetOutputMirror(out)
+	th := resolveTheme(cfg)
+	w.SetStyle(th.Style)
+	columns := tableColumnNames(cfg)
+	header := table.Row{}
+	for _, c := range columns {
+		header = append(header, sortHeader(cfg.Sort, columnHeader(c)))
+	}
+	w.AppendHeader(header)
 
-	for _, pr := range prs {
-		votes := summarizeVotesTyped(pr.Reviewers)
-		title := pr.Title
-		author := pr.CreatedBy.DisplayName
-		repo := pr.Repository.Name
-		st := refShort(pr.SourceRefName) + "->" + refShort(pr.TargetRefName)
-		created := humanize.Time(pr.CreationDate)
-		href := pr.Links.Web.Href
-		status := getPRStatusOverall(cfg, pr)
-		w.AppendRow(table.Row{
-			fmt.Sprintf("%d", pr.PullRequestID),
-			title,
-			author,
-			repo,
-			st,
-			votes,
-			status,
-			created,
-			href,
-		})
+	for gi, g := range groupRecords(cfg, displayed(cfg, recs)) {
+		if g.Key != "" {
+			if gi > 0 {
+				w.AppendSeparator()
+			}
+			w.AppendRow(table.Row{"", fmt.Sprintf("▸ %s (%d)", g.Key, len(g.Recs))})
+			w.AppendSeparator()
+		}
+		for _, pr := range g.Recs {
+			votes := summarizeVotesTyped(pr.Reviewers)
+			if len(pr.Reviewers) == 0 {
+				votes = th.NoReviewers.Sprint(votes)
+			}
+			title := wrapTitle(pr.Title, cfg.TitleWidth, cfg.MaxTitleLines)
+			switch {
+			case pr.ReadyToPublish:
+				title = "[draft ✓ ready] " + title
+			case pr.IsDraft:
+				title = "[draft] " + title
+			}
+			if pr.HasConflicts() {
+				title = "[⚠ conflicts] " + title
+			}
+			if !strings.EqualFold(pr.Status, prStatusActive) && pr.Status != "" {
+				title = "[" + pr.Status + "] " + title
+			}
+			st := refShort(pr.SourceRefName) + "->" + refShort(pr.TargetRefName)
+			if pr.nonDefaultTarget() {
+				st += " ⚠"
+			}
+			created := relTime(cfg, pr.CreationDate.Time)
+			if !pr.CreationDate.IsZero() {
+				created = th.age(created, time.Since(pr.CreationDate.Time))
+			}
+			id := fmt.Sprintf("%d", pr.PullRequestID)
+			if pr.Changed {
+				id = th.Changed.Sprint("● " + id)
+			}
+			row := table.Row{}
+			for _, c := range columns {
+				switch c {
+				case "pr":
+					row = append(row, id)
+				case "title":
+					row = append(row, title)
+				case "description":
+					row = append(row, truncate(plainText(pr.Description), 60))
+				case "author":
+					row = append(row, pr.CreatedBy.DisplayName)
+				case "role":
+					row = append(row, pr.Role)
+				case "org":
+					row = append(row, pr.Org)
+				case "project":
+					row = append(row, pr.Repository.Project.Name)
+				case "repo":
+					row = append(row, pr.Repository.Name)
+				case "branches":
+					row = append(row, st)
+				case "votes":
+					row = append(row, votes)
+				case "gap":
+					row = append(row, formatGap(pr.ApprovalGap))
+				case "wis":
+					row = append(row, formatWorkItems(th, cfg, pr.WorkItems))
+				case "comments":
+					row = append(row, formatComments(pr.Comments))
+				case "labels":
+					row = append(row, formatLabels(pr.pullRequest))
+				case "checks":
+					row = append(row, th.checks(pr.Checks))
+				case "created":
+					row = append(row, created)
+				case "updated":
+					row = append(row, relTime(cfg, pr.Updated))
+				case "url":
+					row = append(row, pr.Links.Web.Href)
+				}
+			}
+			w.AppendRow(row)
+			if cfg.ShowDescription {
+				desc := truncate(plainText(pr.Description), descriptionWidth)
+				if pr.nonDefaultTarget() {
+					desc = strings.TrimSpace("[default branch: " + refShort(pr.DefaultBranch) + "] " + desc)
+				}
+				if desc != "" {
+					w.AppendRow(table.Row{"", "↳ " + desc})
+				}
+			}
+		}
 	}
 
+	w.AppendFooter(table.Row{"", summaryLine(recs)})
 	w.Render()
+	printLimitNotice(out, cfg, len(recs))
 }
 
-func getPRStatusOverall(cfg config, pr pullRequest) string {
-	// Build endpoint: https://dev.azure.com/{org}/{project}/_apis/git/repositories/{repoId}/pullRequests/{pullRequestId}/statuses?api-version=...
-	base := fmt.Sprintf("https://dev.azure.com/%s/%s/_apis/git/repositories/%s/pullRequests/%d/statuses", url.PathEscape(cfg.Org), url.PathEscape(cfg.Project), url.PathEscape(pr.Repository.ID), pr.PullRequestID)
-	q := url.Values{}
-	q.Set("api-version", cfg.ApiVer)
-	endpoint := base + "?" + q.Encode()
+// formatWorkItems renders the WIs cell, e.g. "#12, #34". PRs without work items are
+// marked when --require-work-item is set.
+func formatWorkItems(th theme, cfg config, ids []string) string {
+	if len(ids) == 0 {
+		if cfg.RequireWorkItem {
+			return th.NoReviewers.Sprint("⚠ none")
+		}
+		return ""
+	}
+	return "#" + strings.Join(ids, ", #")
+}
 
-	req, err := http.NewRequest("GET", endpoint, nil)
-	if err != nil {
-		return "Unknown"
+// displayed returns the records to render under --display-limit.
+func displayed(cfg config, recs []prRecord) []prRecord {
+	if cfg.DisplayLimit > 0 && len(recs) > cfg.DisplayLimit {
+		return recs[:cfg.DisplayLimit]
 	}
-	token := base64.StdEncoding.EncodeToString([]byte(":" + cfg.Pat))
-	req.Header.Set("Authorization", "Basic "+token)
-	req.Header.Set("Accept", "application/json")
+	return recs
+}
 
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "Unknown"
+// printLimitNotice tells how many of total PRs were left out by --display-limit.
+func printLimitNotice(w io.Writer, cfg config, total int) {
+	if cfg.DisplayLimit > 0 && total > cfg.DisplayLimit {
+		fmt.Fprintln(w, msg(msgShowing, cfg.DisplayLimit, total))
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode == 401 || resp.StatusCode == 403 {
-		return "Unauthorized"
+}
+
+// summaryLine describes the listed PRs, e.g. "12 pull requests, 3 with failing checks".
+func summaryLine(recs []prRecord) string {
+	s := summarize(recs, time.Now())
+	line := msgN(msgSummary, s.Total)
+	if failing := s.ByChecks["Failed"]; failing > 0 {
+		line += ", " + msg(msgSummaryFailing, failing)
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "Unknown"
+	return line
+}
+
+// printOneline prints "#123 [Passed] +2/3 Title (author)" per PR, coloring the check
+// status when writing to a terminal.
+func printOneline(w io.Writer, cfg config, recs []prRecord) {
+	th := onelineTheme(w, cfg)
+	for _, pr := range displayed(cfg, recs) {
+		fmt.Fprintln(w, onelineText(th, pr))
+	}
+	printLimitNotice(w, cfg, len(recs))
+}
+
+// onelineTheme is the theme for line output: colors only when w is a terminal.
+func onelineTheme(w io.Writer, cfg config) theme {
+	th := resolveTheme(cfg)
+	if !colorEnabled(w) {
+		plain := plainTheme
+		plain.Glyphs = th.Glyphs
+		return plain
+	}
+	return th
+}
+
+func onelineText(th theme, pr prRecord) string {
+	status := "[" + th.checks(pr.Checks) + "]"
+	id := fmt.Sprintf("#%d", pr.PullRequestID)
+	if pr.Changed {
+		id = th.Changed.Sprint("● " + id)
+	}
+	return fmt.Sprintf("%s %s %s %s (%s)", id, status, summarizeVotesTyped(pr.Reviewers), pr.Title, pr.CreatedBy.DisplayName)
+}
+
+// streamOneline enriches prs and prints each one as soon as its calls finish, in
+// completion order, applying the enrichment-dependent filters on the fly.
+// It returns the number of PRs printed.
+func streamOneline(w io.Writer, cfg config, prs []pullRequest, me userIdentity, errs *enrichErrors) int {
+	th := onelineTheme(w, cfg)
+	n := 0
+	for res := range enrichAsync(cfg, prs, errs) {
+		kept := filterRecords(cfg, []prRecord{res.Rec})
+		if len(kept) == 0 {
+			continue
+		}
+		r := kept[0]
+		if cfg.MyWork {
+			r.Role = roleOf(r.pullRequest, me)
+		}
+		fmt.Fprintln(w, onelineText(th, r))
+		n++
 	}
+	return n
+}
 
-	var sr prStatusResponse
-	if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil {
-		return "Unknown"
+// getPRStatusOverall aggregates the PR's statuses into a single word. On failure it
+// returns "Unknown" (or "Unauthorized") together with the error.
+func getPRStatusOverall(ctx context.Context, cfg config, pr pullRequest) (string, error) {
+	statuses, err := apiClient(cfg, 15*time.Second).PullRequestStatuses(ctx, prProject(cfg, pr), pr)
+	if err != nil {
+		var se *httpStatusError
+		if errors.Is(err, azdo.ErrAuthorize) || (errors.As(err, &se) && (se.Code == http.StatusUnauthorized || se.Code == http.StatusForbidden)) {
+			return "Unauthorized", err
+		}
+		return "Unknown", err
 	}
 
-	if len(sr.Value) == 0 {
-		return "No checks"
+	if len(statuses) == 0 {
+		return "No checks", nil
 	}
 
+	now := time.Now()
 	anyPending := false
+	anyStuck := false
 	anyFailed := false
 	anyError := false
 	anySucceeded := false
 	allSucceededOrNA := true
 
-	for _, s := range sr.Value {
+	for _, s := range statuses {
 		state := strings.ToLower(s.State)
 		switch state {
 		case "succeeded", "success":
 			anySucceeded = true
 		case "pending", "inprogress", "in_progress":
-			anyPending = true
+			if isStuckCheck(s, now, cfg.StaleCheckAge) {
+				anyStuck = true
+			} else {
+				anyPending = true
+			}
 			allSucceededOrNA = false
 		case "failed", "failure":
 			anyFailed = true
@@ -294,31 +1362,303 @@ func getPRStatusOverall(cfg config, pr pullRequest) string {
 	}
 
 	if anyFailed || anyError {
-		return "Failed"
+		return "Failed", nil
 	}
 	if anyPending {
-		return "In Progress"
+		return "In Progress", nil
+	}
+	if anyStuck {
+		return "Stuck?", nil
 	}
 	if anySucceeded && allSucceededOrNA {
-		return "Passed"
+		return "Passed", nil
 	}
 	// If we reached here and there were statuses but none conclusive
-	return "Unknown"
+	return "Unknown", nil
+}
+
+// isStuckCheck reports whether the pending status s has not been updated for longer
+// than maxAge, which usually means its pipeline was canceled or its agent died.
+// A zero maxAge disables the detection.
+func isStuckCheck(s prStatus, now time.Time, maxAge time.Duration) bool {
+	last := s.UpdatedDate.Time
+	if last.IsZero() {
+		last = s.CreationDate.Time
+	}
+	if maxAge <= 0 || last.IsZero() {
+		return false
+	}
+	return now.Sub(last) > maxAge
+}
+
+// displayTime normalizes t to the zone timestamps are rendered in:
+// local time by default, UTC when --utc is set.
+func displayTime(cfg config, t time.Time) time.Time {
+	if cfg.UTC {
+		return t.UTC()
+	}
+	return t.Local()
+}
+
+// relTime renders t relative to now, with both sides normalized to the same zone.
+// A zero time (an unparseable API timestamp) renders as "unknown".
+func relTime(cfg config, t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return humanize.RelTime(displayTime(cfg, t), displayTime(cfg, time.Now()), "ago", "from now")
+}
+
+// absTime renders t as a date and time in the zone chosen by displayTime.
+// A zero time renders as "unknown".
+func absTime(cfg config, t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return displayTime(cfg, t).Format("2006-01-02 15:04 MST")
+}
+
+// filterPRs applies the client-side filters from cfg, preserving order.
+func filterPRs(cfg config, prs []pullRequest) []pullRequest {
+	out := prs[:0]
+	for _, pr := range prs {
+		if len(cfg.Repos) > 0 && !slices.ContainsFunc(cfg.Repos, func(r string) bool {
+			return strings.EqualFold(r, pr.Repository.Name) || strings.EqualFold(r, pr.Repository.ID)
+		}) {
+			continue
+		}
+		if cfg.Source != "" && !refMatches(pr.SourceRefName, cfg.Source) {
+			continue
+		}
+		if cfg.Target != "" && !refMatches(pr.TargetRefName, cfg.Target) {
+			continue
+		}
+		if cfg.NoReviewers && len(pr.Reviewers) > 0 {
+			continue
+		}
+		if (cfg.DraftsOnly && !pr.IsDraft) || (cfg.NoDrafts && pr.IsDraft) {
+			continue
+		}
+		if cfg.ConflictsOnly && !pr.HasConflicts() {
+			continue
+		}
+		if len(cfg.Authors) > 0 && !authorMatches(pr.CreatedBy, cfg.Authors) {
+			continue
+		}
+		if cfg.OlderThan > 0 && time.Since(pr.CreationDate.Time) < cfg.OlderThan {
+			continue
+		}
+		out = append(out, pr)
+	}
+	return out
+}
+
+// Enrichment kinds, used as keys when collecting per-PR failures.
+const (
+	enrichStatus    = "status"
+	enrichWorkItems = "workitems"
+	enrichPolicies  = "policies"
+	enrichThreads   = "threads"
+)
+
+// prKey identifies a PR across organizations and projects, whose PR IDs may overlap.
+type prKey struct {
+	Org     string
+	Project string
+	ID      int
+}
+
+func keyOf(pr pullRequest) prKey {
+	return prKey{Org: pr.Org, Project: pr.Repository.Project.Name, ID: pr.PullRequestID}
+}
+
+// String renders k as "PR 123", qualified with the org when listing several.
+func (k prKey) String() string {
+	if k.Org == "" {
+		return fmt.Sprintf("PR %d", k.ID)
+	}
+	if k.Project == "" {
+		return fmt.Sprintf("%s PR %d", k.Org, k.ID)
+	}
+	return fmt.Sprintf("%s/%s PR %d", k.Org, k.Project, k.ID)
+}
+
+// enrichErrors collects failed enrichment calls keyed by PR and enrichment kind.
+// It is safe for concurrent use.
+type enrichErrors struct {
+	mu   sync.Mutex
+	errs map[prKey]map[string]error
+}
+
+func newEnrichErrors() *enrichErrors {
+	return &enrichErrors{errs: map[prKey]map[string]error{}}
+}
+
+func (e *enrichErrors) add(pr pullRequest, kind string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	k := keyOf(pr)
+	if e.errs[k] == nil {
+		e.errs[k] = map[string]error{}
+	}
+	e.errs[k][kind] = err
+	debugLog.Printf("%s: %s enrichment failed: %v", k, kind, err)
 }
 
+// forPR returns the failed enrichment kinds of a PR with their error messages, or nil.
+func (e *enrichErrors) forPR(pr pullRequest) map[string]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	kinds := e.errs[keyOf(pr)]
+	if len(kinds) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(kinds))
+	for k, err := range kinds {
+		out[k] = err.Error()
+	}
+	return out
+}
+
+// len returns the number of PRs with at least one failed enrichment.
+func (e *enrichErrors) len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.errs)
+}
+
+// print writes one compact line per failing PR, e.g. "PR 123: status=timeout, threads=HTTP 403".
+func (e *enrichErrors) print(w io.Writer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	keys := make([]prKey, 0, len(e.errs))
+	for k := range e.errs {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b prKey) int {
+		return cmp.Or(cmp.Compare(a.Org, b.Org), cmp.Compare(a.Project, b.Project), cmp.Compare(a.ID, b.ID))
+	})
+
+	fmt.Fprintf(w, "Enrichment failures (%d PRs):\n", len(keys))
+	for _, key := range keys {
+		kinds := make([]string, 0, len(e.errs[key]))
+		for k := range e.errs[key] {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		parts := make([]string, 0, len(kinds))
+		for _, k := range kinds {
+			parts = append(parts, k+"="+shortError(e.errs[key][k]))
+		}
+		fmt.Fprintf(w, "  %s: %s\n", key, strings.Join(parts, ", "))
+	}
+}
+
+// httpStatusError is a compact error for an unexpected HTTP response status.
+type httpStatusError = azdo.StatusError
+
+// shortError condenses err for single-line summaries.
+func shortError(err error) string {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return "timeout"
+	}
+	return err.Error()
+}
+
+// refShort renders a git ref compactly: branches lose their refs/heads/ prefix,
+// tags are shown as "tag: X" and any other ref type keeps its namespace (e.g. pull/12/merge).
 func refShort(ref string) string {
-	ref = strings.TrimPrefix(ref, "refs/heads/")
-	ref = strings.TrimPrefix(ref, "refs/")
-	return ref
+	switch {
+	case ref == "":
+		return "?"
+	case strings.HasPrefix(ref, "refs/heads/"):
+		return strings.TrimPrefix(ref, "refs/heads/")
+	case strings.HasPrefix(ref, "refs/tags/"):
+		return "tag: " + strings.TrimPrefix(ref, "refs/tags/")
+	default:
+		return strings.TrimPrefix(ref, "refs/")
+	}
 }
 
+// refMatches reports whether ref matches a user-supplied ref filter. The filter may be the
+// full ref (refs/tags/v1), the ref without refs/ (tags/v1), a short branch name or the
+// rendered short form (tag: v1), or a glob such as release/* (see path.Match). Comparison
+// is case-insensitive like Azure DevOps.
+func refMatches(ref, want string) bool {
+	want = strings.TrimSpace(want)
+	candidates := []string{ref, refShort(ref), strings.TrimPrefix(ref, "refs/")}
+	for _, c := range candidates {
+		if isRefGlob(want) {
+			if ok, _ := path.Match(strings.ToLower(want), strings.ToLower(c)); ok {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(c, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRefGlob reports whether a --source/--target value is a glob pattern.
+func isRefGlob(want string) bool {
+	return strings.ContainsAny(want, "*?[")
+}
+
+// parseSince parses a point in time given as RFC3339, a YYYY-MM-DD date (local time)
+// or an age relative to now such as 90m, 36h, 7d or 2w.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	d, err := parseAge(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (use RFC3339, YYYY-MM-DD or an age like 7d)", s)
+	}
+	return now.Add(-d), nil
+}
+
+// parseAge parses a duration, additionally accepting whole days (7d) and weeks (2w).
+func parseAge(s string) (time.Duration, error) {
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		days, err := strconv.Atoi(n)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	if n, ok := strings.CutSuffix(s, "w"); ok {
+		weeks, err := strconv.Atoi(n)
+		if err != nil || weeks < 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(weeks) * 7 * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// noReviewersMarker is shown in the Votes column when nobody was asked to review.
+const noReviewersMarker = "∅ none"
+
 func summarizeVotesTyped(reviewers []reviewer) string {
 	if len(reviewers) == 0 {
-		return "0"
+		return noReviewersMarker
 	}
-	up, down, wait := 0, 0, 0
+	up, down, wait, declined := 0, 0, 0, 0
 	for _, r := range reviewers {
 		switch {
+		case r.HasDeclined:
+			declined++
 		case r.Vote > 0:
 			up++
 		case r.Vote < 0:
@@ -327,16 +1667,22 @@ func summarizeVotesTyped(reviewers []reviewer) string {
 			wait++
 		}
 	}
-	if down > 0 {
-		return fmt.Sprintf("-%d/%d", down, len(reviewers))
-	}
-	if up > 0 {
-		return fmt.Sprintf("+%d/%d", up, len(reviewers))
+	total := len(reviewers) - declined
+	var s string
+	switch {
+	case down > 0:
+		s = fmt.Sprintf("-%d/%d", down, total)
+	case up > 0:
+		s = fmt.Sprintf("+%d/%d", up, total)
+	case wait > 0:
+		s = fmt.Sprintf("~%d/%d", wait, total)
+	default:
+		s = fmt.Sprintf("%d", total)
 	}
-	if wait > 0 {
-		return fmt.Sprintf("~%d/%d", wait, len(reviewers))
+	if declined > 0 {
+		s += fmt.Sprintf(" (%d declined)", declined)
 	}
-	return fmt.Sprintf("%d", len(reviewers))
+	return s
 }
 
 func failUsage(msg string) {
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEnrichErrorsKeyedByOrg(t *testing.T) {
+	a := pullRequest{PullRequestID: 7, Org: "alpha"}
+	b := pullRequest{PullRequestID: 7, Org: "beta"}
+	errs := newEnrichErrors()
+	errs.add(a, enrichStatus, errors.New("timeout"))
+
+	if got := errs.forPR(a); got[enrichStatus] != "timeout" {
+		t.Errorf("forPR(alpha) = %v, want the status error", got)
+	}
+	if got := errs.forPR(b); got != nil {
+		t.Errorf("forPR(beta) = %v, want nil", got)
+	}
+	errs.add(b, enrichThreads, errors.New("HTTP 403"))
+	if errs.len() != 2 {
+		t.Errorf("len = %d, want 2", errs.len())
+	}
+	var sb strings.Builder
+	errs.print(&sb)
+	if !strings.Contains(sb.String(), "alpha PR 7: status=") || !strings.Contains(sb.String(), "beta PR 7: threads=") {
+		t.Errorf("print = %q, want one line per org", sb.String())
+	}
+}
+
+func TestIsStuckCheck(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	maxAge := 2 * time.Hour
+	at := func(d time.Duration) apiTime { return apiTime{Time: now.Add(-d)} }
+	for _, tc := range []struct {
+		name   string
+		s      prStatus
+		maxAge time.Duration
+		want   bool
+	}{
+		{"exactly max age", prStatus{UpdatedDate: at(maxAge)}, maxAge, false},
+		{"just over max age", prStatus{UpdatedDate: at(maxAge + time.Second)}, maxAge, true},
+		{"recently updated", prStatus{CreationDate: at(10 * time.Hour), UpdatedDate: at(time.Minute)}, maxAge, false},
+		{"no update falls back to creation", prStatus{CreationDate: at(3 * time.Hour)}, maxAge, true},
+		{"no update, created recently", prStatus{CreationDate: at(time.Hour)}, maxAge, false},
+		{"no timestamps", prStatus{}, maxAge, false},
+		{"zero max age disables", prStatus{UpdatedDate: at(100 * time.Hour)}, 0, false},
+	} {
+		if got := isStuckCheck(tc.s, now, tc.maxAge); got != tc.want {
+			t.Errorf("%s: isStuckCheck = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSummarizeVotesTyped(t *testing.T) {
+	approved := reviewer{Vote: 10}
+	rejected := reviewer{Vote: -10}
+	waiting := reviewer{Vote: voteWaitingForAuthor}
+	noVote := reviewer{}
+	declined := reviewer{HasDeclined: true}
+	for _, tc := range []struct {
+		name      string
+		reviewers []reviewer
+		want      string
+	}{
+		{"nobody", nil, noReviewersMarker},
+		{"approved", []reviewer{approved, noVote}, "+1/2"},
+		{"declined left out of the total", []reviewer{approved, declined, noVote}, "+1/2 (1 declined)"},
+		{"declined with a vote", []reviewer{{Vote: -10, HasDeclined: true}, approved}, "+1/1 (1 declined)"},
+		{"rejection wins", []reviewer{approved, rejected, declined}, "-1/2 (1 declined)"},
+		{"waiting", []reviewer{waiting, declined}, "-1/1 (1 declined)"},
+		{"no votes yet", []reviewer{noVote, noVote, declined}, "~2/2 (1 declined)"},
+		{"all declined", []reviewer{declined, declined}, "0 (2 declined)"},
+	} {
+		if got := summarizeVotesTyped(tc.reviewers); got != tc.want {
+			t.Errorf("%s: summarizeVotesTyped = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRefShort(t *testing.T) {
+	for ref, want := range map[string]string{
+		"":                        "?",
+		"refs/heads/main":         "main",
+		"refs/heads/feature/x":    "feature/x",
+		"refs/tags/v1.2":          "tag: v1.2",
+		"refs/pull/42/merge":      "pull/42/merge",
+		"refs/notes/commits":      "notes/commits",
+		"refs/remotes/origin/dev": "remotes/origin/dev",
+		"main":                    "main",
+	} {
+		if got := refShort(ref); got != want {
+			t.Errorf("refShort(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
+
+func TestRefMatches(t *testing.T) {
+	for _, tc := range []struct {
+		ref, want string
+		match     bool
+	}{
+		{"refs/heads/main", "main", true},
+		{"refs/heads/main", "MAIN", true},
+		{"refs/heads/main", "refs/heads/main", true},
+		{"refs/heads/main", "heads/main", true},
+		{"refs/heads/main", " main ", true},
+		{"refs/heads/main", "mai", false},
+		{"refs/heads/release/1.0", "release/*", true},
+		{"refs/heads/release/1.0", "Release/*", true},
+		{"refs/heads/main", "release/*", false},
+		{"refs/tags/v1", "tags/v1", true},
+		{"refs/tags/v1", "refs/tags/v1", true},
+		{"refs/tags/v1", "tag: v1", true},
+		{"refs/tags/v1", "v1", false},
+		{"refs/tags/v1.2", "tags/v1.*", true},
+		{"refs/pull/42/merge", "pull/42/merge", true},
+		{"refs/pull/42/merge", "pull/*/merge", true},
+		{"refs/notes/commits", "notes/commits", true},
+		{"refs/notes/commits", "commits", false},
+	} {
+		if got := refMatches(tc.ref, tc.want); got != tc.match {
+			t.Errorf("refMatches(%q, %q) = %v, want %v", tc.ref, tc.want, got, tc.match)
+		}
+	}
+}
+
+func TestEnrichResolvesMissingRepositoryID(t *testing.T) {
+	var statusCalls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/proj/_apis/git/repositories", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"value":[{"id":"r1","name":"web","defaultBranch":"refs/heads/main"}]}`))
+	})
+	mux.HandleFunc("/proj/_apis/git/repositories/r1/pullRequests/7/statuses", func(w http.ResponseWriter, r *http.Request) {
+		statusCalls.Add(1)
+		w.Write([]byte(`{"value":[]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cfg := config{BaseURL: srv.URL, Project: "proj", ApiVer: "7.1", Output: outputTable}
+	enrich := func(repoName string) (prRecord, *enrichErrors) {
+		errs := newEnrichErrors()
+		e := &enricher{cfg: cfg, lim: newAdaptiveLimiter(1, 1, 1), errs: errs, repos: &projectRepos{}}
+		return e.enrich(pullRequest{PullRequestID: 7, Repository: repositoryInfo{Name: repoName}}), errs
+	}
+
+	t.Run("resolved by name", func(t *testing.T) {
+		rec, errs := enrich("Web")
+		if rec.Repository.ID != "r1" || rec.DefaultBranch != "refs/heads/main" {
+			t.Errorf("repository = %+v, default branch %q; want r1 on refs/heads/main", rec.Repository, rec.DefaultBranch)
+		}
+		if rec.Checks != "No checks" || statusCalls.Load() != 1 {
+			t.Errorf("checks = %q after %d status calls, want No checks after 1", rec.Checks, statusCalls.Load())
+		}
+		if errs.len() != 0 {
+			t.Errorf("%d enrichment errors, want none", errs.len())
+		}
+	})
+	t.Run("unknown repository", func(t *testing.T) {
+		statusCalls.Store(0)
+		rec, _ := enrich("api")
+		if rec.Checks != "N/A" || rec.Repository.ID != "" {
+			t.Errorf("checks = %q, repository ID %q; want N/A without an ID", rec.Checks, rec.Repository.ID)
+		}
+		if n := statusCalls.Load(); n != 0 {
+			t.Errorf("%d status calls, want per-PR calls skipped", n)
+		}
+	})
+}
+
+func TestNeedsDefaultBranch(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  config
+		want bool
+	}{
+		{"table", config{Output: outputTable}, true},
+		{"table without branches", config{Output: outputTable, Columns: []string{"pr", "title"}}, false},
+		{"oneline", config{Output: outputTable, Oneline: true}, false},
+		{"oneline filtering", config{Output: outputTable, Oneline: true, NonDefaultTargetOnly: true}, true},
+		{"csv", config{Output: outputCSV}, false},
+		{"csv with description", config{Output: outputCSV, ShowDescription: true}, true},
+		{"json", config{Output: outputJSON}, true},
+		{"count by status", config{Output: outputTable, CountByStatus: true}, false},
+	} {
+		if got := tc.cfg.needsDefaultBranch(); got != tc.want {
+			t.Errorf("%s: needsDefaultBranch = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
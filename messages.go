@@ -0,0 +1,59 @@
+package main
+
+import "fmt"
+
+// Message keys. User-facing result and summary sentences are looked up here rather than
+// written inline, so they can be customized or translated in one place.
+const (
+	msgNoActivePRs     = "no-active-prs"
+	msgNoMatches       = "no-matches"
+	msgNoFailingToOpen = "no-failing-to-open"
+	msgConfirmOpen     = "confirm-open"
+	msgTruncated       = "truncated"
+	msgSummary         = "summary"
+	msgSummaryFailing  = "summary-failing"
+	msgShowing         = "showing"
+)
+
+// pluralForms holds the variants of a message that depends on a count.
+type pluralForms struct {
+	Zero, One, Other string
+}
+
+// messages is the English catalog. Plural messages take the count as their first argument.
+var messages = map[string]any{
+	msgNoActivePRs:     "No %s pull requests found.",
+	msgNoMatches:       "No %s pull requests matched the filters.",
+	msgNoFailingToOpen: "No PRs with failing checks to open.",
+	msgConfirmOpen:     pluralForms{One: "Open %d PR in the browser?", Other: "Open %d PRs in the browser?"},
+	msgTruncated:       pluralForms{One: "Results may be truncated at %d PR; increase --top to see more.", Other: "Results may be truncated at %d PRs; increase --top to see more."},
+	msgSummary:         pluralForms{Zero: "No pull requests", One: "%d pull request", Other: "%d pull requests"},
+	msgSummaryFailing:  "%d with failing checks",
+	msgShowing:         "Showing %d of %d — use --display-limit 0 for all.",
+}
+
+// msg formats the message for key.
+func msg(key string, args ...any) string {
+	s, _ := messages[key].(string)
+	if s == "" {
+		return key
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+// msgN formats the plural message for key using n to pick the form; n is also the
+// first format argument.
+func msgN(key string, n int, args ...any) string {
+	forms, ok := messages[key].(pluralForms)
+	if !ok {
+		return key
+	}
+	s := forms.Other
+	switch {
+	case n == 0 && forms.Zero != "":
+		s = forms.Zero
+	case n == 1 && forms.One != "":
+		s = forms.One
+	}
+	return fmt.Sprintf(s, append([]any{n}, args...)...)
+}
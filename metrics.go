@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// prSummary holds aggregate counts over a set of enriched PRs.
+type prSummary struct {
+	Total       int
+	ByChecks    map[string]int
+	NoReviewers int
+	OldestAge   time.Duration
+}
+
+func summarize(recs []prRecord, now time.Time) prSummary {
+	s := prSummary{Total: len(recs), ByChecks: map[string]int{}}
+	for _, r := range recs {
+		s.ByChecks[r.Checks]++
+		if len(r.Reviewers) == 0 {
+			s.NoReviewers++
+		}
+		if !r.CreationDate.IsZero() {
+			s.OldestAge = max(s.OldestAge, now.Sub(r.CreationDate.Time))
+		}
+	}
+	return s
+}
+
+// countByStatusLine renders s as "active=12 failing=3 inprogress=2 stuck=0 passing=7 ..."
+// for --count-by-status. The keys are fixed so scripts can rely on them.
+func countByStatusLine(s prSummary) string {
+	return fmt.Sprintf("active=%d failing=%d inprogress=%d stuck=%d passing=%d nochecks=%d unknown=%d",
+		s.Total,
+		s.ByChecks["Failed"],
+		s.ByChecks["In Progress"],
+		s.ByChecks["Stuck?"],
+		s.ByChecks["Passed"],
+		s.ByChecks["No checks"],
+		s.ByChecks["Unknown"]+s.ByChecks["Unauthorized"]+s.ByChecks["N/A"])
+}
+
+// promLabelEscaper escapes label values as the Prometheus text format requires.
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promLabel renders name="value" for the Prometheus text format.
+func promLabel(name, value string) string {
+	return name + `="` + promLabelEscaper.Replace(value) + `"`
+}
+
+// promMetrics renders s in the Prometheus text exposition format.
+func promMetrics(cfg config, s prSummary) string {
+	labels := promLabel("org", cfg.Org) + "," + promLabel("project", strings.Join(cfg.Projects, ","))
+	var b strings.Builder
+	fmt.Fprintln(&b, "# HELP lazydevops_pull_requests Number of active pull requests.")
+	fmt.Fprintln(&b, "# TYPE lazydevops_pull_requests gauge")
+	fmt.Fprintf(&b, "lazydevops_pull_requests{%s} %d\n", labels, s.Total)
+
+	fmt.Fprintln(&b, "# HELP lazydevops_pull_requests_by_checks Number of active pull requests per overall check status.")
+	fmt.Fprintln(&b, "# TYPE lazydevops_pull_requests_by_checks gauge")
+	statuses := make([]string, 0, len(s.ByChecks))
+	for st := range s.ByChecks {
+		statuses = append(statuses, st)
+	}
+	sort.Strings(statuses)
+	for _, st := range statuses {
+		fmt.Fprintf(&b, "lazydevops_pull_requests_by_checks{%s,%s} %d\n", labels, promLabel("checks", st), s.ByChecks[st])
+	}
+
+	fmt.Fprintln(&b, "# HELP lazydevops_pull_requests_without_reviewers Number of active pull requests with no reviewers assigned.")
+	fmt.Fprintln(&b, "# TYPE lazydevops_pull_requests_without_reviewers gauge")
+	fmt.Fprintf(&b, "lazydevops_pull_requests_without_reviewers{%s} %d\n", labels, s.NoReviewers)
+
+	fmt.Fprintln(&b, "# HELP lazydevops_oldest_pull_request_age_seconds Age of the oldest active pull request.")
+	fmt.Fprintln(&b, "# TYPE lazydevops_oldest_pull_request_age_seconds gauge")
+	fmt.Fprintf(&b, "lazydevops_oldest_pull_request_age_seconds{%s} %.0f\n", labels, s.OldestAge.Seconds())
+	return b.String()
+}
+
+// pushLabel renders a grouping label path segment, using the Pushgateway's base64
+// form for values that contain a slash.
+func pushLabel(name, value string) string {
+	if strings.Contains(value, "/") {
+		return "/" + name + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	return "/" + name + "/" + url.PathEscape(value)
+}
+
+// pushMetrics replaces the metrics of the configured job/instance group on a Pushgateway.
+func pushMetrics(cfg config, s prSummary) error {
+	endpoint := strings.TrimRight(cfg.Pushgateway, "/") + "/metrics" + pushLabel("job", cfg.PushJob)
+	if cfg.PushInstance != "" {
+		endpoint += pushLabel("instance", cfg.PushInstance)
+	}
+	req, err := http.NewRequest("PUT", endpoint, bytes.NewBufferString(promMetrics(cfg, s)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := httpClient(cfg, 15*time.Second).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &httpStatusError{Code: resp.StatusCode}
+	}
+	return nil
+}
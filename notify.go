@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// notification is a desktop notification about a PR event seen by --watch --notify.
+type notification struct {
+	Title string
+	Body  string
+}
+
+// prSnapshot is what --notify compares between refreshes of a PR.
+type prSnapshot struct {
+	Checks string
+	// Votes maps reviewer IDs to their vote label.
+	Votes map[string]string
+}
+
+func snapshotOf(r prRecord) prSnapshot {
+	s := prSnapshot{Checks: r.Checks, Votes: map[string]string{}}
+	for _, rv := range r.Reviewers {
+		s.Votes[rv.ID] = voteLabel(rv)
+	}
+	return s
+}
+
+// prEvents compares recs with the snapshots of the previous refresh and returns the
+// notifications for new PRs, and for new votes and failed checks on PRs authored by
+// me. The first refresh (prev nil) notifies nothing.
+func prEvents(prev map[prKey]prSnapshot, recs []prRecord, me userIdentity) []notification {
+	if prev == nil {
+		return nil
+	}
+	var out []notification
+	for _, r := range recs {
+		id := "PR #" + strconv.Itoa(r.PullRequestID)
+		old, seen := prev[keyOf(r.pullRequest)]
+		if !seen {
+			out = append(out, notification{
+				Title: "New " + id,
+				Body:  fmt.Sprintf("%s\n%s in %s", r.Title, r.CreatedBy.DisplayName, r.Repository.Name),
+			})
+			continue
+		}
+		if !isAuthor(r.pullRequest, me) {
+			continue
+		}
+		for _, rv := range r.Reviewers {
+			label := voteLabel(rv)
+			if label != "no vote" && old.Votes[rv.ID] != label {
+				out = append(out, notification{Title: fmt.Sprintf("%s %s %s", rv.DisplayName, label, id), Body: r.Title})
+			}
+		}
+		if r.Checks == "Failed" && old.Checks != "Failed" {
+			out = append(out, notification{Title: "Checks failed on " + id, Body: r.Title})
+		}
+	}
+	return out
+}
+
+// notify shows n as a native desktop notification: notify-send on Linux and BSD,
+// osascript on macOS and a PowerShell balloon tip on Windows.
+func notify(n notification) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		script := `Add-Type -AssemblyName System.Windows.Forms; ` +
+			`$n = New-Object System.Windows.Forms.NotifyIcon; $n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; ` +
+			`$n.ShowBalloonTip(10000, ` + psString(n.Title) + `, ` + psString(n.Body) + `, 'Info'); Start-Sleep -Seconds 10; $n.Dispose()`
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %s with title %s", strconv.Quote(n.Body), strconv.Quote(n.Title)))
+	default:
+		cmd = exec.Command("notify-send", "--app-name=lazydevops", n.Title, n.Body)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// reap the process without holding up the refresh
+	go cmd.Wait()
+	return nil
+}
+
+// psString quotes s as a PowerShell single-quoted string.
+func psString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
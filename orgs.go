@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// orgConnection is what a multi-organization run needs to talk to one organization.
+type orgConnection struct {
+	Org      string
+	BaseURL  string
+	Projects []string
+	Pat      string
+	ApiVer   string
+}
+
+// resolvePAT finds the PAT for org: the environment variable named by the config file
+// (LAZY_DEV_OPS_PAT by default), then the OS keyring with --auth pat, then the file.
+// It also returns the variable's name and where the PAT came from, for --explain-config.
+func resolvePAT(fc fileConfig, auth, org, fileSrc string) (pat, patEnv, source string) {
+	patEnv = envVarPrimaryPAT
+	if fc.PatEnv != "" {
+		patEnv = fc.PatEnv
+	}
+	if pat = os.Getenv(patEnv); pat != "" {
+		return pat, patEnv, "env " + patEnv
+	}
+	if strings.ToLower(auth) == authPAT {
+		if pat = keyringPAT(org); pat != "" {
+			return pat, patEnv, "keyring"
+		}
+	}
+	if fc.Pat != "" {
+		return fc.Pat, patEnv, fileSrc
+	}
+	return "", patEnv, "unset"
+}
+
+// orgConnections builds the connections of a multi-organization run from several
+// profiles or several --org values; it returns nil for a single organization.
+// projects are the --project values given explicitly, which apply to every organization.
+func orgConnections(fc fileConfig, orgs, projects, profiles []string, auth, apiVer string, apiVerSet bool, baseURL string) ([]orgConnection, error) {
+	if len(profiles) > 1 && len(orgs) > 1 {
+		return nil, errors.New("use either several --org values or several profiles, not both")
+	}
+	if len(orgs) > 1 && baseURL != "" {
+		return nil, errors.New("--base-url names a single collection; use a profile per collection to list several")
+	}
+	version := func(pfc fileConfig, org string) string {
+		if v, _ := pfc.apiVersionFor(org); v != "" && !apiVerSet {
+			return v
+		}
+		return apiVer
+	}
+	var conns []orgConnection
+	switch {
+	case len(profiles) > 1:
+		for _, name := range profiles {
+			pfc, err := fc.withProfile(name)
+			if err != nil {
+				return nil, fmt.Errorf("--profile: %w", err)
+			}
+			if pfc.Org == "" && pfc.BaseURL != "" {
+				pfc.Org, _ = collectionName(pfc.BaseURL)
+			}
+			if pfc.Org == "" {
+				return nil, fmt.Errorf("--profile: profile %q sets no org", name)
+			}
+			c := orgConnection{Org: pfc.Org, BaseURL: strings.TrimSuffix(pfc.BaseURL, "/"), Projects: projects, ApiVer: version(pfc, pfc.Org)}
+			if len(c.Projects) == 0 && pfc.Project != "" {
+				c.Projects = []string{pfc.Project}
+			}
+			if len(c.Projects) == 0 {
+				return nil, fmt.Errorf("--profile: profile %q sets no project and --project is not given", name)
+			}
+			c.Pat, _, _ = resolvePAT(pfc, auth, pfc.Org, sourceFile)
+			conns = append(conns, c)
+		}
+	case len(orgs) > 1:
+		if len(projects) == 0 {
+			return nil, errors.New("--project is required with several organizations")
+		}
+		for _, org := range orgs {
+			c := orgConnection{Org: org, Projects: projects, ApiVer: version(fc, org)}
+			c.Pat, _, _ = resolvePAT(fc, auth, org, sourceFile)
+			conns = append(conns, c)
+		}
+	}
+	return conns, nil
+}
+
+// multiOrg reports whether the PR list spans several organizations.
+func (cfg config) multiOrg() bool {
+	return len(cfg.Orgs) > 1
+}
+
+// withOrg returns cfg switched to the organization of c.
+func (cfg config) withOrg(c orgConnection) config {
+	cfg.Org, cfg.BaseURL, cfg.Pat, cfg.ApiVer = c.Org, c.BaseURL, c.Pat, c.ApiVer
+	cfg.Projects = c.Projects
+	cfg.Project = c.Projects[0]
+	return cfg
+}
+
+// forOrg returns cfg switched to the named organization of a multi-organization run;
+// cfg itself when the organization is not one of them.
+func (cfg config) forOrg(org string) config {
+	for _, c := range cfg.Orgs {
+		if strings.EqualFold(c.Org, org) {
+			return cfg.withOrg(c)
+		}
+	}
+	return cfg
+}
+
+// fetchAllOrgs runs fetchSelected for every organization of a multi-organization run
+// concurrently and tags each PR with its organization. The identities of the user in
+// the organizations are merged, so --my-work recognizes the user in all of them.
+func fetchAllOrgs(cfg config) ([]pullRequest, userIdentity, error) {
+	type result struct {
+		prs []pullRequest
+		me  userIdentity
+		err error
+	}
+	results := make([]result, len(cfg.Orgs))
+	var wg sync.WaitGroup
+	for i, c := range cfg.Orgs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ocfg := cfg.withOrg(c)
+			ocfg.Orgs = nil
+			prs, me, err := fetchSelected(ocfg)
+			for j := range prs {
+				prs[j].Org = c.Org
+			}
+			results[i] = result{prs, me, err}
+		}()
+	}
+	wg.Wait()
+
+	var all []pullRequest
+	var me userIdentity
+	for i, r := range results {
+		if r.err != nil {
+			return nil, me, fmt.Errorf("organization %s: %w", cfg.Orgs[i].Org, r.err)
+		}
+		all = append(all, r.prs...)
+		if me.ID == "" {
+			me = r.me
+		} else {
+			me.addAliases(r.me.UniqueName)
+			me.addAliases(r.me.Aliases...)
+		}
+	}
+	return all, me, nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// --output values.
+const (
+	outputTable            = "table"
+	outputCSV              = "csv"
+	outputJSON             = "json"
+	outputMarkdown         = "markdown"
+	outputNDJSON           = "ndjson"
+	outputNDJSONWithErrors = "ndjson-with-errors"
+	outputTemplate         = "template"
+)
+
+// templateFuncs are the functions available to --template besides the text/template
+// built-ins.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join":     strings.Join,
+	"ref":      refShort,
+	"truncate": func(n int, s string) string { return truncate(s, n) },
+	"votes":    summarizeVotesTyped,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+}
+
+// parseOutputTemplate parses the --template text.
+func parseOutputTemplate(text string) (*template.Template, error) {
+	return template.New("output").Funcs(templateFuncs).Parse(text)
+}
+
+// printTemplate writes tmpl executed on each record, one line per PR.
+func printTemplate(w io.Writer, tmpl *template.Template, recs []prRecord) error {
+	for _, r := range recs {
+		if err := tmpl.Execute(w, r); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ndjsonRecord is one line of NDJSON output. EnrichmentErrors maps enrichment kinds
+// (status, policies, workitems) to the error that call failed with.
+type ndjsonRecord struct {
+	prRecord
+	EnrichmentErrors map[string]string `json:"enrichmentErrors,omitempty"`
+}
+
+// printJSON writes v, e.g. the enriched records, as indented JSON.
+func printJSON(w io.Writer, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// orEmpty returns s, or an empty slice when s is nil, so that JSON output lists nothing
+// as [] rather than null.
+func orEmpty[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// writeJSONFile saves the enriched records as a JSON array to path, atomically.
+func writeJSONFile(path string, recs []prRecord) error {
+	b, err := json.MarshalIndent(recs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(b, '\n'), 0o644)
+}
+
+// printNDJSON writes one JSON object per PR, optionally including its enrichment failures.
+func printNDJSON(w io.Writer, recs []prRecord, errs *enrichErrors, withErrors bool) error {
+	enc := json.NewEncoder(w)
+	for _, r := range recs {
+		line := ndjsonRecord{prRecord: r}
+		if withErrors {
+			line.EnrichmentErrors = errs.forPR(r.pullRequest)
+		}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openOutput returns where results are written: the --out file, or stdout. The
+// returned func closes the file and must be called once writing is done.
+func openOutput(cfg config) (io.Writer, func()) {
+	if cfg.OutFile == "" {
+		return os.Stdout, func() {}
+	}
+	f, err := os.Create(cfg.OutFile)
+	if err != nil {
+		log.Fatalln("Error: --out:", err)
+	}
+	return f, func() {
+		if err := f.Close(); err != nil {
+			log.Fatalln("Error: --out:", err)
+		}
+	}
+}
+
+// markdownEscaper escapes characters that would end a markdown link text early.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+// printMarkdown writes the records as a markdown table with titles linked to the PRs,
+// ready to paste into a wiki page or chat.
+func printMarkdown(w io.Writer, cfg config, recs []prRecord) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"PR", "Title", "Author", "Repo", "Source->Target", "Votes", "Checks", "Created"})
+	for _, r := range recs {
+		title := markdownEscaper.Replace(r.Title)
+		if href := r.Links.Web.Href; href != "" {
+			title = "[" + title + "](" + href + ")"
+		}
+		t.AppendRow(table.Row{
+			fmt.Sprintf("#%d", r.PullRequestID),
+			title,
+			r.CreatedBy.DisplayName,
+			r.Repository.Name,
+			refShort(r.SourceRefName) + " → " + refShort(r.TargetRefName),
+			summarizeVotesTyped(r.Reviewers),
+			r.Checks,
+			relTime(cfg, r.CreationDate.Time),
+		})
+	}
+	t.RenderMarkdown()
+}
+
+// printCSV writes the records as CSV with a header row, for spreadsheets and reporting
+// scripts. Times are RFC 3339 in the zone chosen by --utc and votes are counted per kind rather than summarized.
+func printCSV(w io.Writer, cfg config, recs []prRecord) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "title", "author", "org", "project", "repository", "source", "target", "status", "mergeStatus", "draft",
+		"reviewers", "approvals", "rejections", "waiting", "checks", "created", "url"})
+	for _, r := range recs {
+		reviewers, approvals, rejections, waiting := 0, 0, 0, 0
+		for _, rv := range r.Reviewers {
+			if rv.HasDeclined {
+				continue
+			}
+			reviewers++
+			switch {
+			case rv.Vote > 0:
+				approvals++
+			case rv.Vote == voteWaitingForAuthor:
+				waiting++
+			case rv.Vote < 0:
+				rejections++
+			}
+		}
+		created := ""
+		if !r.CreationDate.Time.IsZero() {
+			created = displayTime(cfg, r.CreationDate.Time).Format(time.RFC3339)
+		}
+		cw.Write([]string{
+			strconv.Itoa(r.PullRequestID),
+			r.Title,
+			r.CreatedBy.DisplayName,
+			r.Org,
+			r.Repository.Project.Name,
+			r.Repository.Name,
+			refShort(r.SourceRefName),
+			refShort(r.TargetRefName),
+			r.Status,
+			r.MergeStatus,
+			strconv.FormatBool(r.IsDraft),
+			strconv.Itoa(reviewers),
+			strconv.Itoa(approvals),
+			strconv.Itoa(rejections),
+			strconv.Itoa(waiting),
+			r.Checks,
+			created,
+			r.Links.Web.Href,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
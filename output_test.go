@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrintCSVCreatedHonorsUTC(t *testing.T) {
+	created := time.Date(2024, 5, 1, 14, 3, 0, 0, time.FixedZone("CEST", 2*3600))
+	recs := []prRecord{{pullRequest: pullRequest{PullRequestID: 1, CreationDate: apiTime{Time: created}}}}
+
+	var sb strings.Builder
+	if err := printCSV(&sb, config{UTC: true}, recs); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), ",2024-05-01T12:03:00Z,") {
+		t.Errorf("printCSV with --utc = %q, want the created time in UTC", sb.String())
+	}
+}
+
+func TestPrintJSONEmptyList(t *testing.T) {
+	var checks []prCheck
+	var sb strings.Builder
+	if err := printJSON(&sb, orEmpty(checks)); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(sb.String()); got != "[]" {
+		t.Errorf("printJSON(orEmpty(nil)) = %q, want []", got)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+)
+
+// followInterval is how often --follow polls a running pipeline.
+const followInterval = 5 * time.Second
+
+// runState is the word shown for a run: its result once completed, else its state.
+func runState(run azdo.PipelineRun) string {
+	if run.State == "completed" && run.Result != "" {
+		return run.Result
+	}
+	return run.State
+}
+
+// followRun polls run of pipeline until it completes, printing its state and the state
+// of its stages whenever they change, and returns the completed run.
+func followRun(cfg config, pipeline int, run azdo.PipelineRun) azdo.PipelineRun {
+	th := resolveTheme(cfg)
+	c := apiClient(cfg, 15*time.Second)
+	ctx := context.Background()
+	last := ""
+	stages := map[string]string{}
+	for {
+		if state := runState(run); state != last {
+			fmt.Printf("%s  run %s: %s\n", time.Now().Format("15:04:05"), run.Name, th.checkState(state))
+			last = state
+		}
+		records, err := c.Timeline(ctx, cfg.Project, run.ID)
+		if err != nil {
+			// the timeline appears only once an agent picked the run up
+			debugLog.Printf("timeline of run %d: %v", run.ID, err)
+		}
+		sort.SliceStable(records, func(i, j int) bool { return records[i].Order < records[j].Order })
+		for _, r := range records {
+			if r.Type != "Stage" {
+				continue
+			}
+			state := r.State
+			if r.State == "completed" && r.Result != "" {
+				state = r.Result
+			}
+			if stages[r.ID] != state {
+				fmt.Printf("%s    stage %s: %s\n", time.Now().Format("15:04:05"), r.Name, th.checkState(state))
+				stages[r.ID] = state
+			}
+		}
+		if run.State == "completed" {
+			return run
+		}
+		time.Sleep(followInterval)
+		if run, err = c.PipelineRun(ctx, cfg.Project, pipeline, run.ID); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	}
+}
+
+// runPipelineRun runs "pipeline run <name-or-id>": it queues a run, optionally of
+// another branch and with variables, and with --follow waits for it to finish.
+func runPipelineRun(args []string) {
+	branch := flag.String("branch", "", "Branch to run (name or full ref; default: the pipeline's default branch)")
+	vars := keyValues{}
+	flag.Var(vars, "var", "Set a pipeline variable as key=value (repeatable); it must be settable at queue time")
+	follow := flag.Bool("follow", false, "Print state changes of the run and its stages until it finishes")
+	cfg := getConfig(args)
+	if len(cfg.Args) != 1 {
+		failUsage("usage: lazydevops pipeline run <name-or-id> [--branch X] [--var key=value] [--follow]")
+	}
+	if cfg.multiProject() {
+		failUsage("pipeline run needs a single --project")
+	}
+	def, err := findPipeline(cfg, cfg.Args[0])
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	ref := ""
+	if *branch != "" {
+		ref = branchRef(*branch)
+	}
+	run, err := apiClient(cfg, 15*time.Second).RunPipeline(context.Background(), cfg.Project, def.ID, ref, vars)
+	if err != nil {
+		log.Fatalln("Error: queuing the run:", err)
+	}
+	fmt.Printf("Queued run %s of %s (#%d)\n", run.Name, run.Pipeline.Name, run.ID)
+	if run.Links.Web.Href != "" {
+		fmt.Println(run.Links.Web.Href)
+	}
+	if !*follow {
+		return
+	}
+	run = followRun(cfg, def.ID, run)
+	if run.Result != "succeeded" {
+		os.Exit(exitRunFailed)
+	}
+}
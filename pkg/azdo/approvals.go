@@ -0,0 +1,69 @@
+package azdo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ApprovalStep is the part of an approval assigned to one approver.
+type ApprovalStep struct {
+	AssignedApprover Identity `json:"assignedApprover"`
+	ActualApprover   Identity `json:"actualApprover"`
+	Status           string   `json:"status"`
+	Comment          string   `json:"comment,omitempty"`
+}
+
+// Approval is a check of an environment or stage waiting for approvers before a
+// pipeline run may continue.
+type Approval struct {
+	ID                   string         `json:"id"`
+	Status               string         `json:"status"`
+	CreatedOn            Time           `json:"createdOn"`
+	Instructions         string         `json:"instructions,omitempty"`
+	MinRequiredApprovers int            `json:"minRequiredApprovers"`
+	Steps                []ApprovalStep `json:"steps"`
+	Pipeline             struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Owner struct {
+			ID    int    `json:"id"`
+			Name  string `json:"name"`
+			Links Links  `json:"_links"`
+		} `json:"owner"`
+	} `json:"pipeline"`
+}
+
+// Approvals lists the approvals of project in state (e.g. "pending"; all when empty)
+// together with their steps.
+func (c *Client) Approvals(ctx context.Context, project, state string) ([]Approval, error) {
+	q := url.Values{}
+	q.Set("$expand", "steps")
+	if state != "" {
+		q.Set("state", state)
+	}
+	var resp struct {
+		Value []Approval `json:"value"`
+	}
+	if err := c.Get(ctx, c.URL(project, "_apis/pipelines/approvals", q), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// UpdateApproval approves or rejects (status "approved" or "rejected") approval id in
+// project as the authenticated user.
+func (c *Client) UpdateApproval(ctx context.Context, project, id, status, comment string) (Approval, error) {
+	body := []map[string]string{{"approvalId": id, "status": status, "comment": comment}}
+	var resp struct {
+		Value []Approval `json:"value"`
+	}
+	if err := c.Send(ctx, http.MethodPatch, c.URL(project, "_apis/pipelines/approvals", nil), body, &resp); err != nil {
+		return Approval{}, err
+	}
+	if len(resp.Value) == 0 {
+		return Approval{}, errors.New("the server returned no approval")
+	}
+	return resp.Value[0], nil
+}
@@ -0,0 +1,91 @@
+package azdo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ZeroObjectID is the object ID that deletes a ref when set as its new object ID.
+const ZeroObjectID = "0000000000000000000000000000000000000000"
+
+// GitUser is the author or committer of a commit.
+type GitUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Date  Time   `json:"date"`
+}
+
+// BranchStats is a branch of a repository with its latest commit and how far it is
+// ahead of and behind the default branch.
+type BranchStats struct {
+	// Name is the branch name without refs/heads/.
+	Name   string `json:"name"`
+	Commit struct {
+		CommitID  string  `json:"commitId"`
+		Author    GitUser `json:"author"`
+		Committer GitUser `json:"committer"`
+	} `json:"commit"`
+	AheadCount  int `json:"aheadCount"`
+	BehindCount int `json:"behindCount"`
+	// IsBaseVersion is set for the default branch the counts compare to.
+	IsBaseVersion bool `json:"isBaseVersion"`
+}
+
+// BranchStats lists the branches of repository repoID in project.
+func (c *Client) BranchStats(ctx context.Context, project, repoID string) ([]BranchStats, error) {
+	var resp struct {
+		Value []BranchStats `json:"value"`
+	}
+	if err := c.Get(ctx, c.URL(project, "_apis/git/repositories/"+url.PathEscape(repoID)+"/stats/branches", nil), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// RefUpdate moves the ref Name (e.g. refs/heads/main) from OldObjectID to NewObjectID;
+// ZeroObjectID as the new object ID deletes it.
+type RefUpdate struct {
+	Name        string `json:"name"`
+	OldObjectID string `json:"oldObjectId"`
+	NewObjectID string `json:"newObjectId"`
+}
+
+// RefUpdateResult is the outcome of one RefUpdate.
+type RefUpdateResult struct {
+	Name          string `json:"name"`
+	Success       bool   `json:"success"`
+	UpdateStatus  string `json:"updateStatus"`
+	CustomMessage string `json:"customMessage"`
+}
+
+// UpdateRefs applies updates to repository repoID in project. Updates are applied
+// independently; check Success of each result.
+func (c *Client) UpdateRefs(ctx context.Context, project, repoID string, updates []RefUpdate) ([]RefUpdateResult, error) {
+	var resp struct {
+		Value []RefUpdateResult `json:"value"`
+	}
+	if err := c.Send(ctx, http.MethodPost, c.URL(project, "_apis/git/repositories/"+url.PathEscape(repoID)+"/refs", nil), updates, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// ActivePullRequests lists all active pull requests of repository repoID in project.
+func (c *Client) ActivePullRequests(ctx context.Context, project, repoID string) ([]PullRequest, error) {
+	const pageSize = 1000
+	criteria := url.Values{}
+	criteria.Set("searchCriteria.repositoryId", repoID)
+	criteria.Set("searchCriteria.status", "active")
+	var all []PullRequest
+	for {
+		page, err := c.PullRequests(ctx, project, criteria, len(all), pageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < pageSize {
+			return all, nil
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package azdo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// BuildDefinition is a pipeline definition, as referenced by its builds.
+type BuildDefinition struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"`
+}
+
+// Build is a run of a pipeline.
+type Build struct {
+	ID            int             `json:"id"`
+	BuildNumber   string          `json:"buildNumber"`
+	Status        string          `json:"status"`
+	Result        string          `json:"result,omitempty"`
+	Reason        string          `json:"reason,omitempty"`
+	QueueTime     Time            `json:"queueTime"`
+	StartTime     Time            `json:"startTime"`
+	FinishTime    Time            `json:"finishTime"`
+	SourceBranch  string          `json:"sourceBranch"`
+	SourceVersion string          `json:"sourceVersion,omitempty"`
+	Definition    BuildDefinition `json:"definition"`
+	RequestedFor  Identity        `json:"requestedFor"`
+	Links         Links           `json:"_links"`
+}
+
+// Builds lists the builds of project matching q (e.g. definitions, branchName, $top),
+// newest first.
+func (c *Client) Builds(ctx context.Context, project string, q url.Values) ([]Build, error) {
+	var resp struct {
+		Value []Build `json:"value"`
+	}
+	if err := c.Get(ctx, c.URL(project, "_apis/build/builds", q), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// Build loads a build of project by ID.
+func (c *Client) Build(ctx context.Context, project string, id int) (Build, error) {
+	var b Build
+	err := c.Get(ctx, c.URL(project, "_apis/build/builds/"+strconv.Itoa(id), nil), &b)
+	return b, err
+}
+
+// BuildDefinitions lists the pipeline definitions of project whose name matches name,
+// which may contain * wildcards; an empty name lists all of them.
+func (c *Client) BuildDefinitions(ctx context.Context, project, name string) ([]BuildDefinition, error) {
+	q := url.Values{}
+	if name != "" {
+		q.Set("name", name)
+	}
+	var resp struct {
+		Value []BuildDefinition `json:"value"`
+	}
+	if err := c.Get(ctx, c.URL(project, "_apis/build/definitions", q), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// TimelineRecord is a stage, job, task or other step of a build's timeline.
+type TimelineRecord struct {
+	ID           string `json:"id"`
+	ParentID     string `json:"parentId,omitempty"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	Order        int    `json:"order"`
+	State        string `json:"state"`
+	Result       string `json:"result,omitempty"`
+	StartTime    Time   `json:"startTime"`
+	FinishTime   Time   `json:"finishTime"`
+	ErrorCount   int    `json:"errorCount"`
+	WarningCount int    `json:"warningCount"`
+	Log          *struct {
+		ID int `json:"id"`
+	} `json:"log,omitempty"`
+	Issues []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"issues,omitempty"`
+}
+
+// Timeline lists the records of the timeline of build id in project.
+func (c *Client) Timeline(ctx context.Context, project string, id int) ([]TimelineRecord, error) {
+	var resp struct {
+		Records []TimelineRecord `json:"records"`
+	}
+	if err := c.Get(ctx, c.URL(project, fmt.Sprintf("_apis/build/builds/%d/timeline", id), nil), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Records, nil
+}
+
+// BuildLog returns the lines of log logID of build id in project, starting at the 1-based
+// line startLine; a startLine of 0 returns the whole log.
+func (c *Client) BuildLog(ctx context.Context, project string, id, logID, startLine int) ([]string, error) {
+	var resp struct {
+		Value []string `json:"value"`
+	}
+	q := url.Values{}
+	if startLine > 0 {
+		q.Set("startLine", strconv.Itoa(startLine))
+	}
+	if err := c.Get(ctx, c.URL(project, fmt.Sprintf("_apis/build/builds/%d/logs/%d", id, logID), q), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
@@ -0,0 +1,228 @@
+// Package azdo is a small client for the Azure DevOps REST API: a Client for
+// authenticated requests against one organization, typed methods for pull requests,
+// projects and repositories, and the API types they return. Requests made through an
+// http.Client using RetryTransport are retried when throttled.
+//
+//	c := &azdo.Client{Org: "contoso", APIVersion: "7.1", Authorize: azdo.PAT(pat)}
+//	prs, err := c.PullRequests(ctx, "web", nil, 0, 100)
+package azdo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Hosts of Azure DevOps Services; organizations are found below them.
+const (
+	DefaultBaseURL  = "https://dev.azure.com"
+	identityBaseURL = "https://vssps.dev.azure.com"
+)
+
+// MaxResponseBytes caps how much of a response body is buffered in memory.
+const MaxResponseBytes = 32 << 20
+
+// Debug receives diagnostics such as retries and unparseable timestamps. It is silent
+// by default.
+var Debug = log.New(io.Discard, "debug: ", 0)
+
+// ErrAuthorize wraps errors of Client.Authorize, e.g. when no token could be obtained.
+var ErrAuthorize = errors.New("authorizing request")
+
+// Client performs authenticated JSON requests against one organization of Azure DevOps
+// Services or one collection of Azure DevOps Server.
+// The zero value is not usable; at least Org (or BaseURL) and Authorize must be set.
+type Client struct {
+	// BaseURL is the URL of an Azure DevOps Server collection, such as
+	// https://tfs.corp.local/tfs/DefaultCollection. When empty, Org is an organization
+	// of Azure DevOps Services at DefaultBaseURL.
+	BaseURL string
+	Org     string
+	// APIVersion is added to URLs built with URL that do not set one.
+	APIVersion string
+	// Authorize adds credentials to a request, e.g. PAT(pat).
+	Authorize func(*http.Request) error
+	// HTTP sends the requests; http.DefaultClient when nil.
+	HTTP *http.Client
+	// Timeout bounds each request including reading its response; 0 means none.
+	Timeout time.Duration
+}
+
+// PAT authorizes requests with a personal access token.
+func PAT(pat string) func(*http.Request) error {
+	token := base64.StdEncoding.EncodeToString([]byte(":" + pat))
+	return func(req *http.Request) error {
+		req.Header.Set("Authorization", "Basic "+token)
+		return nil
+	}
+}
+
+// Bearer authorizes requests with an OAuth access token returned by token, which is
+// called for every request and should cache.
+func Bearer(token func() (string, error)) func(*http.Request) error {
+	return func(req *http.Request) error {
+		t, err := token()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+t)
+		return nil
+	}
+}
+
+// StatusError is returned for responses with a non-2xx status. Message is the
+// server's explanation, if it sent one.
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("HTTP %d: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("HTTP %d", e.Code)
+}
+
+// OrgURL returns the URL of the organization or collection, without a trailing slash.
+func (c *Client) OrgURL() string {
+	if c.BaseURL != "" {
+		return strings.TrimSuffix(c.BaseURL, "/")
+	}
+	return DefaultBaseURL + "/" + url.PathEscape(c.Org)
+}
+
+// WebURL returns the web page at path (already escaped) in project, e.g.
+// "_git/web/pullrequest/12", or in the organization when project is empty.
+func (c *Client) WebURL(project, path string) string {
+	u := c.OrgURL() + "/"
+	if project != "" {
+		u += url.PathEscape(project) + "/"
+	}
+	return u + strings.TrimPrefix(path, "/")
+}
+
+// URL returns the endpoint for path (e.g. "_apis/git/pullrequests", already escaped)
+// in project, or in the organization when project is empty. The api-version query
+// parameter defaults to c.APIVersion.
+func (c *Client) URL(project, path string, q url.Values) string {
+	return withAPIVersion(c.WebURL(project, path), q, c.APIVersion)
+}
+
+// IdentityURL returns the endpoint for path of the identity service, which Azure DevOps
+// Services hosts apart from the organization and Azure DevOps Server in the collection.
+func (c *Client) IdentityURL(path string, q url.Values) string {
+	base := c.OrgURL()
+	if c.BaseURL == "" {
+		base = identityBaseURL + "/" + url.PathEscape(c.Org)
+	}
+	return withAPIVersion(base+"/"+strings.TrimPrefix(path, "/"), q, c.APIVersion)
+}
+
+// withAPIVersion appends the query q to u, adding api-version unless q sets it.
+func withAPIVersion(u string, q url.Values, version string) string {
+	if q == nil {
+		q = url.Values{}
+	}
+	if !q.Has("api-version") && version != "" {
+		q = cloneValues(q)
+		q.Set("api-version", version)
+	}
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	return u
+}
+
+func cloneValues(q url.Values) url.Values {
+	c := make(url.Values, len(q))
+	for k, v := range q {
+		c[k] = v
+	}
+	return c
+}
+
+// Get requests endpoint and decodes the JSON response into v.
+func (c *Client) Get(ctx context.Context, endpoint string, v any) error {
+	return c.Send(ctx, http.MethodGet, endpoint, nil, v)
+}
+
+// Send performs a request with body (if not nil) encoded as JSON and decodes the JSON
+// response into v (if not nil).
+func (c *Client) Send(ctx context.Context, method, endpoint string, body, v any) error {
+	b, err := c.Raw(ctx, method, endpoint, body)
+	if err != nil || v == nil || len(b) == 0 {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Raw performs a request like Send and returns the undecoded response body, for callers
+// that decode it in their own way.
+func (c *Client) Raw(ctx context.Context, method, endpoint string, body any) ([]byte, error) {
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		rd = bytes.NewReader(b)
+	}
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, rd)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Authorize(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAuthorize, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	switch body.(type) {
+	case nil:
+	case JSONPatch:
+		req.Header.Set("Content-Type", "application/json-patch+json")
+	default:
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		se := &StatusError{Code: resp.StatusCode}
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&apiErr) == nil {
+			se.Message = apiErr.Message
+		}
+		return nil, se
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > MaxResponseBytes {
+		return nil, fmt.Errorf("response from %s exceeds %d MiB", req.URL.Host, MaxResponseBytes>>20)
+	}
+	return b, nil
+}
@@ -0,0 +1,52 @@
+package azdo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// PipelineRun is a run of a pipeline as returned by the Pipelines API. Its ID is the ID
+// of the corresponding build.
+type PipelineRun struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	State        string `json:"state"`
+	Result       string `json:"result,omitempty"`
+	CreatedDate  Time   `json:"createdDate"`
+	FinishedDate Time   `json:"finishedDate"`
+	Pipeline     struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"pipeline"`
+	Links Links `json:"_links"`
+}
+
+// RunPipeline queues a run of pipeline in project. branch (a full ref) selects the
+// branch of the pipeline's repository, the default branch when empty; variables
+// override the pipeline's variables that are settable at queue time.
+func (c *Client) RunPipeline(ctx context.Context, project string, pipeline int, branch string, variables map[string]string) (PipelineRun, error) {
+	body := map[string]any{}
+	if branch != "" {
+		body["resources"] = map[string]any{
+			"repositories": map[string]any{"self": map[string]string{"refName": branch}},
+		}
+	}
+	if len(variables) > 0 {
+		vars := make(map[string]any, len(variables))
+		for k, v := range variables {
+			vars[k] = map[string]string{"value": v}
+		}
+		body["variables"] = vars
+	}
+	var run PipelineRun
+	err := c.Send(ctx, http.MethodPost, c.URL(project, fmt.Sprintf("_apis/pipelines/%d/runs", pipeline), nil), body, &run)
+	return run, err
+}
+
+// PipelineRun loads run of pipeline in project.
+func (c *Client) PipelineRun(ctx context.Context, project string, pipeline, run int) (PipelineRun, error) {
+	var r PipelineRun
+	err := c.Get(ctx, c.URL(project, fmt.Sprintf("_apis/pipelines/%d/runs/%d", pipeline, run), nil), &r)
+	return r, err
+}
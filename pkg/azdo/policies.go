@@ -0,0 +1,74 @@
+package azdo
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Policy type IDs of the built-in branch policies.
+const (
+	PolicyMinimumReviewers  = "fa4e907d-c16b-4a4c-9dfa-4906e5d171dd"
+	PolicyBuild             = "0609b952-1397-4640-95ec-e00a01b2c241"
+	PolicyRequiredReviewers = "fd2167ab-b0be-447a-8ec8-39368250530e"
+	PolicyCommentResolution = "c6a1889d-b943-4856-b76f-9e46bb6b0df2"
+	PolicyWorkItemLinking   = "40e92b44-2fe1-4dd6-b3d8-74a9c21d0c6e"
+	PolicyMergeStrategy     = "fa4e907d-c16b-4a4c-9dfa-4916e5d171ab"
+)
+
+// PolicyConfiguration is a branch policy configured on a project, repository or branch.
+type PolicyConfiguration struct {
+	ID         int  `json:"id"`
+	IsEnabled  bool `json:"isEnabled"`
+	IsBlocking bool `json:"isBlocking"`
+	IsDeleted  bool `json:"isDeleted"`
+	Type       struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName"`
+	} `json:"type"`
+	Settings PolicySettings `json:"settings"`
+}
+
+// HasType reports whether p is of the policy type with the given ID.
+func (p PolicyConfiguration) HasType(id string) bool {
+	return strings.EqualFold(p.Type.ID, id)
+}
+
+// PolicySettings holds the settings of the built-in policy types; each type uses only
+// some of them.
+type PolicySettings struct {
+	// Minimum number of reviewers, and required reviewers.
+	MinimumApproverCount int  `json:"minimumApproverCount"`
+	CreatorVoteCounts    bool `json:"creatorVoteCounts"`
+	ResetOnSourcePush    bool `json:"resetOnSourcePush"`
+	// Build validation.
+	BuildDefinitionID       int     `json:"buildDefinitionId"`
+	ManualQueueOnly         bool    `json:"manualQueueOnly"`
+	QueueOnSourceUpdateOnly bool    `json:"queueOnSourceUpdateOnly"`
+	ValidDuration           float64 `json:"validDuration"` // minutes, 0 for no expiry
+	DisplayName             string  `json:"displayName"`
+	// Required reviewers.
+	RequiredReviewerIDs []string `json:"requiredReviewerIds"`
+	FilenamePatterns    []string `json:"filenamePatterns"`
+	Message             string   `json:"message"`
+	// Merge strategy.
+	AllowSquash        bool `json:"allowSquash"`
+	AllowNoFastForward bool `json:"allowNoFastForward"`
+	AllowRebase        bool `json:"allowRebase"`
+	AllowRebaseMerge   bool `json:"allowRebaseMerge"`
+}
+
+// BranchPolicies lists the policies that apply to branch ref (e.g. refs/heads/main) of
+// repository repoID in project, including those configured for the whole project.
+func (c *Client) BranchPolicies(ctx context.Context, project, repoID, ref string) ([]PolicyConfiguration, error) {
+	q := url.Values{}
+	q.Set("repositoryId", repoID)
+	q.Set("refName", ref)
+	var resp struct {
+		Value []PolicyConfiguration `json:"value"`
+	}
+	if err := c.Get(ctx, c.URL(project, "_apis/git/policy/configurations", q), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
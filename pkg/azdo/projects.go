@@ -0,0 +1,51 @@
+package azdo
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// Projects lists the projects of the organization.
+func (c *Client) Projects(ctx context.Context) ([]ProjectInfo, error) {
+	// $top raises the default page of 100 projects; larger organizations are rare.
+	q := url.Values{}
+	q.Set("$top", "1000")
+	var resp struct {
+		Value []ProjectInfo `json:"value"`
+	}
+	if err := c.Get(ctx, c.URL("", "_apis/projects", q), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// Repositories lists the git repositories of project.
+func (c *Client) Repositories(ctx context.Context, project string) ([]Repository, error) {
+	var resp struct {
+		Value []Repository `json:"value"`
+	}
+	if err := c.Get(ctx, c.URL(project, "_apis/git/repositories", nil), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// LastPush returns when repository repoID of project was last pushed to, or the zero
+// time when it never was.
+func (c *Client) LastPush(ctx context.Context, project, repoID string) (time.Time, error) {
+	q := url.Values{}
+	q.Set("$top", "1")
+	var resp struct {
+		Value []struct {
+			Date Time `json:"date"`
+		} `json:"value"`
+	}
+	if err := c.Get(ctx, c.URL(project, "_apis/git/repositories/"+url.PathEscape(repoID)+"/pushes", q), &resp); err != nil {
+		return time.Time{}, err
+	}
+	if len(resp.Value) == 0 {
+		return time.Time{}, nil
+	}
+	return resp.Value[0].Date.Time, nil
+}
@@ -0,0 +1,91 @@
+package azdo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// PullRequests lists pull requests of project (or of the organization when project is
+// empty) matching the searchCriteria.* parameters in criteria, skipping skip and
+// returning at most top; top <= 0 leaves the page size to the server.
+func (c *Client) PullRequests(ctx context.Context, project string, criteria url.Values, skip, top int) ([]PullRequest, error) {
+	q := cloneValues(criteria)
+	if top > 0 {
+		q.Set("$top", strconv.Itoa(top))
+	}
+	if skip > 0 {
+		q.Set("$skip", strconv.Itoa(skip))
+	}
+	body, err := c.Raw(ctx, http.MethodGet, c.URL(project, "_apis/git/pullrequests", q), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Value []PullRequest `json:"value"`
+		Count int           `json:"count"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&resp); err != nil {
+		// if strict decode fails due to preview changes, re-decode the same bytes permissively
+		Debug.Printf("strict decode failed (%v); retrying permissively", err)
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Value, nil
+}
+
+// PullRequest loads a pull request by ID from project, or from the whole organization
+// when project is empty.
+func (c *Client) PullRequest(ctx context.Context, project string, id int) (PullRequest, error) {
+	var pr PullRequest
+	err := c.Get(ctx, c.URL(project, fmt.Sprintf("_apis/git/pullrequests/%d", id), nil), &pr)
+	return pr, err
+}
+
+// PullRequestURL returns the endpoint of pr's resource in project, or of path
+// (e.g. "/threads") below it.
+func (c *Client) PullRequestURL(project string, pr PullRequest, path string) string {
+	return c.URL(project, fmt.Sprintf("_apis/git/repositories/%s/pullRequests/%d%s",
+		url.PathEscape(pr.Repository.ID), pr.PullRequestID, path), nil)
+}
+
+// PullRequestStatuses lists every status posted to pr in project, including outdated
+// ones of earlier iterations.
+func (c *Client) PullRequestStatuses(ctx context.Context, project string, pr PullRequest) ([]Status, error) {
+	var resp struct {
+		Value []Status `json:"value"`
+	}
+	if err := c.Get(ctx, c.PullRequestURL(project, pr, "/statuses"), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// ArtifactURL returns the artifact URI of pr that work items link to, e.g.
+// vstfs:///Git/PullRequestId/{project ID}%2F{repository ID}%2F{pull request ID}.
+func (pr PullRequest) ArtifactURL() string {
+	return fmt.Sprintf("vstfs:///Git/PullRequestId/%s%%2F%s%%2F%d", pr.Repository.Project.ID, pr.Repository.ID, pr.PullRequestID)
+}
+
+// PullRequestWorkItems returns the IDs of the work items linked to pr in project.
+func (c *Client) PullRequestWorkItems(ctx context.Context, project string, pr PullRequest) ([]string, error) {
+	var resp struct {
+		Value []ResourceRef `json:"value"`
+	}
+	if err := c.Get(ctx, c.PullRequestURL(project, pr, "/workitems"), &resp); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(resp.Value))
+	for _, r := range resp.Value {
+		ids = append(ids, r.ID)
+	}
+	return ids, nil
+}
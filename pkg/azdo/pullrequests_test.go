@@ -0,0 +1,32 @@
+package azdo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPullRequestsPermissiveFallbackMakesOneRequest(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		// previewFlag is not a field of PullRequest, so the strict decode fails
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count":1,"value":[{"pullRequestId":42,"title":"Fix","previewFlag":true}]}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL, APIVersion: "7.1", Authorize: PAT("pat")}
+	prs, err := c.PullRequests(context.Background(), "web", nil, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 || prs[0].PullRequestID != 42 || prs[0].Title != "Fix" {
+		t.Errorf("PullRequests = %+v, want PR 42", prs)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("server hit %d times, want 1", n)
+	}
+}
@@ -0,0 +1,155 @@
+package azdo
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// maxRetries is how often a throttled or transiently failing request is retried.
+const maxRetries = 4
+
+// Bounds of the delay between retries.
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+	// maxRetryAfter caps how long a Retry-After header can make us wait.
+	maxRetryAfter = 2 * time.Minute
+)
+
+// throttleCount counts the 429 responses received so far, including retried ones.
+var throttleCount atomic.Int64
+
+// Throttles returns how many responses were throttled (HTTP 429) so far, including
+// retried ones. Callers watch it to lower their concurrency.
+func Throttles() int64 {
+	return throttleCount.Load()
+}
+
+type throttleCounterKey struct{}
+
+// throttleCounter is a counter added by CountThrottles, linked to the counters of the
+// enclosing contexts.
+type throttleCounter struct {
+	n      *atomic.Int64
+	parent *throttleCounter
+}
+
+// CountThrottles returns a context that makes RetryTransport add the 429 responses of
+// requests made with it to n, so a caller can tell its own throttled requests from
+// those of concurrent ones. Counters of enclosing contexts keep counting too.
+func CountThrottles(ctx context.Context, n *atomic.Int64) context.Context {
+	parent, _ := ctx.Value(throttleCounterKey{}).(*throttleCounter)
+	return context.WithValue(ctx, throttleCounterKey{}, &throttleCounter{n: n, parent: parent})
+}
+
+// RetryTransport retries requests the server throttled (429), honoring Retry-After,
+// and, for idempotent methods, requests that failed with a 5xx gateway or availability
+// error or a network error, with jittered exponential backoff.
+type RetryTransport struct {
+	// Base sends the requests; http.DefaultTransport when nil.
+	Base http.RoundTripper
+}
+
+func (t RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := base.RoundTrip(req)
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			throttleCount.Add(1)
+			c, _ := req.Context().Value(throttleCounterKey{}).(*throttleCounter)
+			for ; c != nil; c = c.parent {
+				c.n.Add(1)
+			}
+		}
+		wait, retry := retryDelay(req, resp, err, attempt)
+		if !retry || attempt >= maxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			// drain the body so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		Debug.Printf("%s %s: %s; retry %d in %s", req.Method, req.URL.Path, retryReason(resp, err), attempt+1, wait.Round(time.Millisecond))
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryDelay reports whether the outcome of attempt is worth retrying and how long to
+// wait first.
+func retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if req.Context().Err() != nil {
+		// canceled or timed out by the client: retrying cannot succeed
+		return 0, false
+	}
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead ||
+		req.Method == http.MethodPut || req.Method == http.MethodDelete
+	switch {
+	case err != nil:
+		return backoff(attempt), idempotent
+	case resp.StatusCode == http.StatusTooManyRequests:
+		// throttled requests were not processed, so any method can be repeated
+		if d := retryAfter(resp); d > 0 {
+			return d, true
+		}
+		return backoff(attempt), true
+	case resp.StatusCode == http.StatusBadGateway, resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout, resp.StatusCode == http.StatusInternalServerError:
+		if d := retryAfter(resp); d > 0 {
+			return d, idempotent
+		}
+		return backoff(attempt), idempotent
+	}
+	return 0, false
+}
+
+// backoff is the delay before retry attempt+1: exponential with full jitter in its
+// upper half, so concurrent callers do not retry in lockstep.
+func backoff(attempt int) time.Duration {
+	d := min(retryBaseDelay<<attempt, retryMaxDelay)
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses the Retry-After header, given in seconds or as an HTTP date.
+// It returns 0 when the header is missing or invalid.
+func retryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = time.Until(t)
+	}
+	return min(max(d, 0), maxRetryAfter)
+}
+
+// retryReason describes a failed attempt for the debug log.
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
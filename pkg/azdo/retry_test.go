@@ -0,0 +1,52 @@
+package azdo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCountThrottlesPerContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/throttled" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: RetryTransport{}}
+	send := func(ctx context.Context, path string) {
+		// a body without GetBody cannot be replayed, so the 429 is returned without a retry
+		body := io.MultiReader(strings.NewReader("{}"))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+path, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	var outer, mine, other atomic.Int64
+	before := Throttles()
+	ctx := CountThrottles(context.Background(), &outer)
+	send(CountThrottles(ctx, &mine), "/throttled")
+	send(CountThrottles(ctx, &other), "/ok")
+	send(context.Background(), "/throttled")
+
+	if mine.Load() != 1 || other.Load() != 0 {
+		t.Errorf("counted %d and %d throttles, want 1 and 0", mine.Load(), other.Load())
+	}
+	if outer.Load() != 1 {
+		t.Errorf("enclosing counter counted %d throttles, want 1", outer.Load())
+	}
+	if n := Throttles() - before; n != 2 {
+		t.Errorf("Throttles grew by %d, want 2", n)
+	}
+}
@@ -0,0 +1,57 @@
+package azdo
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts are tried in order when decoding API timestamps. Besides RFC3339 some
+// preview endpoints emit timestamps without a zone (assumed UTC) or with a space separator.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.9999999",
+	"2006-01-02T15:04:05.9999999Z0700",
+	"2006-01-02 15:04:05.9999999Z07:00",
+	"2006-01-02 15:04:05.9999999",
+}
+
+// Time is a time.Time that decodes the timestamp variants returned by Azure DevOps.
+// Unparseable values decode to the zero time with a debug warning instead of failing the
+// whole response.
+type Time struct {
+	time.Time
+}
+
+func (t *Time) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil || s == "" {
+		// null, empty or non-string values carry no usable timestamp
+		t.Time = time.Time{}
+		return nil
+	}
+	for _, layout := range timeLayouts {
+		if v, err := time.Parse(layout, s); err == nil {
+			t.Time = v
+			return nil
+		}
+	}
+	// legacy WCF form: /Date(1700000000000)/ or /Date(1700000000000+0100)/
+	if ms, ok := strings.CutPrefix(s, "/Date("); ok {
+		ms = strings.TrimSuffix(ms, ")/")
+		// the offset follows the milliseconds, which may themselves be negative
+		if len(ms) > 0 {
+			if i := strings.IndexAny(ms[1:], "+-"); i >= 0 {
+				ms = ms[:i+1]
+			}
+		}
+		if n, err := strconv.ParseInt(ms, 10, 64); err == nil {
+			t.Time = time.UnixMilli(n).UTC()
+			return nil
+		}
+	}
+	Debug.Printf("unrecognized timestamp %q; treating as unknown", s)
+	t.Time = time.Time{}
+	return nil
+}
@@ -0,0 +1,44 @@
+package azdo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{"rfc3339", `"2024-03-01T10:20:30Z"`, time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{"rfc3339 fraction", `"2024-03-01T10:20:30.1234567Z"`, time.Date(2024, 3, 1, 10, 20, 30, 123456700, time.UTC)},
+		{"rfc3339 offset", `"2024-03-01T12:20:30+02:00"`, time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{"no zone", `"2024-03-01T10:20:30.5"`, time.Date(2024, 3, 1, 10, 20, 30, 500000000, time.UTC)},
+		{"compact offset", `"2024-03-01T12:20:30.5+0200"`, time.Date(2024, 3, 1, 10, 20, 30, 500000000, time.UTC)},
+		{"space offset", `"2024-03-01 12:20:30+02:00"`, time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{"space no zone", `"2024-03-01 10:20:30"`, time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{"wcf", `"/Date(1700000000000)/"`, time.UnixMilli(1700000000000)},
+		{"wcf plus offset", `"/Date(1700000000000+0100)/"`, time.UnixMilli(1700000000000)},
+		{"wcf minus offset", `"/Date(1700000000000-0500)/"`, time.UnixMilli(1700000000000)},
+		{"wcf negative", `"/Date(-1000)/"`, time.UnixMilli(-1000)},
+		{"wcf empty", `"/Date()/"`, time.Time{}},
+		{"wcf unterminated", `"/Date("`, time.Time{}},
+		{"garbage", `"yesterday"`, time.Time{}},
+		{"empty", `""`, time.Time{}},
+		{"null", `null`, time.Time{}},
+		{"number", `42`, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Time
+			if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+				t.Fatalf("Unmarshal(%s) error: %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got.Time, tt.want)
+			}
+		})
+	}
+}
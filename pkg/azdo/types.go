@@ -0,0 +1,122 @@
+package azdo
+
+import "strings"
+
+// Identity is a user or group as referenced by other resources.
+type Identity struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+}
+
+// RepositoryInfo is the repository reference embedded in a pull request.
+type RepositoryInfo struct {
+	ID      string      `json:"id"`
+	Name    string      `json:"name"`
+	Project ProjectInfo `json:"project"`
+}
+
+// ProjectInfo is a project of the organization.
+type ProjectInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Repository is a git repository as returned by the repositories API.
+type Repository struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	DefaultBranch string `json:"defaultBranch"`
+	// Size is the size of the repository in bytes.
+	Size       int64  `json:"size"`
+	IsDisabled bool   `json:"isDisabled"`
+	WebURL     string `json:"webUrl"`
+}
+
+// Reviewer is a reviewer of a pull request and their vote.
+type Reviewer struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+	Vote        int    `json:"vote"`
+	// HasDeclined is set when the reviewer opted out of the review; such reviewers
+	// are left out of the vote totals.
+	HasDeclined bool `json:"hasDeclined"`
+	// IsFlagged is set when the reviewer was flagged for attention in the web UI.
+	IsFlagged  bool `json:"isFlagged"`
+	IsRequired bool `json:"isRequired"`
+	// IsContainer is set for groups and teams, which vote when one of their members does.
+	IsContainer bool `json:"isContainer"`
+}
+
+// Links holds the links of a resource the tool uses.
+type Links struct {
+	Web struct {
+		Href string `json:"href"`
+	} `json:"web"`
+}
+
+// PullRequest is a pull request as listed by the API. Its JSON field names are the
+// API's.
+type PullRequest struct {
+	PullRequestID int            `json:"pullRequestId"`
+	Title         string         `json:"title"`
+	Description   string         `json:"description"`
+	IsDraft       bool           `json:"isDraft"`
+	Status        string         `json:"status"`
+	CreationDate  Time           `json:"creationDate"`
+	Repository    RepositoryInfo `json:"repository"`
+	CreatedBy     Identity       `json:"createdBy"`
+	SourceRefName string         `json:"sourceRefName"`
+	TargetRefName string         `json:"targetRefName"`
+	Reviewers     []Reviewer     `json:"reviewers"`
+	Links         Links          `json:"_links"`
+	// Org is the organization of the PR when listing several; it is not part of the
+	// API's response and is set by the caller.
+	Org string `json:"org,omitempty"`
+	// MergeStatus is the outcome of the server's trial merge, e.g. succeeded or conflicts.
+	MergeStatus string `json:"mergeStatus,omitempty"`
+	// LastMergeSourceCommit is the source commit last merged; completing a PR must name it.
+	LastMergeSourceCommit *CommitRef `json:"lastMergeSourceCommit,omitempty"`
+	// Labels are the tags of the PR.
+	Labels []Label `json:"labels,omitempty"`
+}
+
+// Label is a tag on a pull request.
+type Label struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+// HasConflicts reports whether the server's trial merge of pr failed on conflicts.
+func (pr PullRequest) HasConflicts() bool {
+	return strings.EqualFold(pr.MergeStatus, "conflicts")
+}
+
+// CommitRef references a commit.
+type CommitRef struct {
+	CommitID string `json:"commitId"`
+}
+
+// StatusContext identifies the service that posted a status.
+type StatusContext struct {
+	Name  string `json:"name"`
+	Genre string `json:"genre"`
+}
+
+// Status is a status posted to a pull request, e.g. by a build.
+type Status struct {
+	State        string        `json:"state"`
+	Description  string        `json:"description"`
+	Context      StatusContext `json:"context"`
+	TargetURL    string        `json:"targetUrl"`
+	CreationDate Time          `json:"creationDate"`
+	UpdatedDate  Time          `json:"updatedDate"`
+}
+
+// ResourceRef is a reference to another resource, such as a linked work item.
+type ResourceRef struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
@@ -0,0 +1,215 @@
+package azdo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxWorkItemsPerCall is the most work items the work items API returns per call.
+const maxWorkItemsPerCall = 200
+
+// WorkItem is a work item with the fields that were requested, keyed by reference name
+// such as System.Title.
+type WorkItem struct {
+	ID     int            `json:"id"`
+	Rev    int            `json:"rev"`
+	Fields map[string]any `json:"fields"`
+}
+
+// Field renders the value of field name as text: identities by their display name,
+// other values as they are; "" when the field is not set.
+func (wi WorkItem) Field(name string) string {
+	switch v := wi.Fields[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any:
+		if s, ok := v["displayName"].(string); ok {
+			return s
+		}
+	}
+	return fmt.Sprint(wi.Fields[name])
+}
+
+// JSONPatch is a JSON Patch document, the body of work item updates. Requests with it
+// are sent as application/json-patch+json.
+type JSONPatch []PatchOperation
+
+// PatchOperation is one operation of a JSONPatch.
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// AddArtifactLink links the work item id in project to the artifact at artifactURL,
+// e.g. a pull request's ArtifactURL, under the link type name such as "Pull Request".
+func (c *Client) AddArtifactLink(ctx context.Context, project string, id int, artifactURL, name string) error {
+	patch := JSONPatch{{
+		Op:   "add",
+		Path: "/relations/-",
+		Value: map[string]any{
+			"rel":        "ArtifactLink",
+			"url":        artifactURL,
+			"attributes": map[string]string{"name": name},
+		},
+	}}
+	return c.Send(ctx, http.MethodPatch, c.URL(project, fmt.Sprintf("_apis/wit/workitems/%d", id), nil), patch, nil)
+}
+
+// QueryResult is the result of a work item query.
+type QueryResult struct {
+	// QueryType is "flat", "tree" or "oneHop".
+	QueryType string
+	// Columns are the fields the query selects, in order.
+	Columns []FieldRef
+	// IDs are the work items the query returned in order; for tree and one-hop
+	// queries every work item of the returned links, once.
+	IDs []int
+}
+
+// FieldRef names a work item field, e.g. System.Title ("Title").
+type FieldRef struct {
+	ReferenceName string `json:"referenceName"`
+	Name          string `json:"name"`
+}
+
+// Query is a saved work item query.
+type Query struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// IsFolder is set for query folders, which cannot be run.
+	IsFolder bool `json:"isFolder"`
+}
+
+// queryResponse is the response of the WIQL API.
+type queryResponse struct {
+	QueryType string     `json:"queryType"`
+	Columns   []FieldRef `json:"columns"`
+	WorkItems []struct {
+		ID int `json:"id"`
+	} `json:"workItems"`
+	WorkItemRelations []struct {
+		Source *struct {
+			ID int `json:"id"`
+		} `json:"source"`
+		Target struct {
+			ID int `json:"id"`
+		} `json:"target"`
+	} `json:"workItemRelations"`
+}
+
+func (r queryResponse) result() *QueryResult {
+	res := &QueryResult{QueryType: r.QueryType, Columns: r.Columns}
+	seen := map[int]bool{}
+	add := func(id int) {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			res.IDs = append(res.IDs, id)
+		}
+	}
+	for _, wi := range r.WorkItems {
+		add(wi.ID)
+	}
+	for _, rel := range r.WorkItemRelations {
+		if rel.Source != nil {
+			add(rel.Source.ID)
+		}
+		add(rel.Target.ID)
+	}
+	return res
+}
+
+// topQuery returns the query parameters limiting a query to top results (top <= 0
+// leaves the limit to the server).
+func topQuery(top int) url.Values {
+	q := url.Values{}
+	if top > 0 {
+		q.Set("$top", strconv.Itoa(top))
+	}
+	return q
+}
+
+// RunWIQL runs the WIQL query wiql in project, returning at most top work items.
+func (c *Client) RunWIQL(ctx context.Context, project, wiql string, top int) (*QueryResult, error) {
+	var resp queryResponse
+	if err := c.Send(ctx, http.MethodPost, c.URL(project, "_apis/wit/wiql", topQuery(top)), map[string]string{"query": wiql}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.result(), nil
+}
+
+// RunQuery runs the saved query with the given ID in project, returning at most top
+// work items.
+func (c *Client) RunQuery(ctx context.Context, project, id string, top int) (*QueryResult, error) {
+	var resp queryResponse
+	if err := c.Get(ctx, c.URL(project, "_apis/wit/wiql/"+url.PathEscape(id), topQuery(top)), &resp); err != nil {
+		return nil, err
+	}
+	return resp.result(), nil
+}
+
+// SavedQuery looks up the saved query or folder at path in project, e.g.
+// "Shared Queries/Team/Active bugs", or by its ID.
+func (c *Client) SavedQuery(ctx context.Context, project, path string) (*Query, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	var q Query
+	if err := c.Get(ctx, c.URL(project, "_apis/wit/queries/"+strings.Join(segments, "/"), nil), &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// QueryWIQL runs a WIQL query in project and returns the IDs of the work items it
+// selected, at most top of them (top <= 0 leaves the limit to the server).
+func (c *Client) QueryWIQL(ctx context.Context, project, wiql string, top int) ([]int, error) {
+	res, err := c.RunWIQL(ctx, project, wiql, top)
+	if err != nil {
+		return nil, err
+	}
+	return res.IDs, nil
+}
+
+// WorkItems loads the work items with the given IDs, in that order, with the given
+// fields (all when empty). Work items that do not exist or are not visible are skipped.
+func (c *Client) WorkItems(ctx context.Context, project string, ids []int, fields []string) ([]WorkItem, error) {
+	items := make([]WorkItem, 0, len(ids))
+	for len(ids) > 0 {
+		batch := ids[:min(len(ids), maxWorkItemsPerCall)]
+		ids = ids[len(batch):]
+		strs := make([]string, len(batch))
+		for i, id := range batch {
+			strs[i] = strconv.Itoa(id)
+		}
+		q := url.Values{}
+		q.Set("ids", strings.Join(strs, ","))
+		q.Set("errorPolicy", "omit")
+		if len(fields) > 0 {
+			q.Set("fields", strings.Join(fields, ","))
+		}
+		var resp struct {
+			Value []*WorkItem `json:"value"`
+		}
+		if err := c.Get(ctx, c.URL(project, "_apis/wit/workitems", q), &resp); err != nil {
+			return nil, err
+		}
+		for _, wi := range resp.Value {
+			// errorPolicy=omit returns null for missing work items
+			if wi != nil {
+				items = append(items, *wi)
+			}
+		}
+	}
+	return items, nil
+}
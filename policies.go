@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+)
+
+// policyEvaluation is the evaluation of one branch policy against a pull request.
+type policyEvaluation struct {
+	Status        string  `json:"status"`
+	StartedDate   apiTime `json:"startedDate"`
+	CompletedDate apiTime `json:"completedDate"`
+	// Context holds policy-specific details, e.g. the build a build policy queued.
+	Context struct {
+		BuildID int `json:"buildId"`
+	} `json:"context"`
+	Configuration struct {
+		IsEnabled  bool `json:"isEnabled"`
+		IsBlocking bool `json:"isBlocking"`
+		Type       struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"displayName"`
+		} `json:"type"`
+		Settings struct {
+			MinimumApproverCount int    `json:"minimumApproverCount"`
+			CreatorVoteCounts    bool   `json:"creatorVoteCounts"`
+			DisplayName          string `json:"displayName"`
+		} `json:"settings"`
+	} `json:"configuration"`
+}
+
+// fetchPolicyEvaluations lists the branch policy evaluations for pr.
+func fetchPolicyEvaluations(ctx context.Context, cfg config, pr pullRequest) ([]policyEvaluation, error) {
+	if pr.Repository.Project.ID == "" {
+		return nil, fmt.Errorf("project ID missing from pull request")
+	}
+	artifact := fmt.Sprintf("vstfs:///CodeReview/CodeReviewId/%s/%d", pr.Repository.Project.ID, pr.PullRequestID)
+	q := url.Values{}
+	q.Set("artifactId", artifact)
+	q.Set("api-version", policyAPIVersion(cfg))
+	endpoint := apiClient(cfg, 0).URL(prProject(cfg, pr), "_apis/policy/evaluations", q)
+	var resp struct {
+		Value []policyEvaluation `json:"value"`
+	}
+	if err := apiClient(cfg, 15*time.Second).Get(ctx, endpoint, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// policyAPIVersion returns the configured API version, marked as preview since the
+// policy evaluations endpoint is only available as a preview API.
+func policyAPIVersion(cfg config) string {
+	if strings.Contains(cfg.ApiVer, "-preview") {
+		return cfg.ApiVer
+	}
+	return cfg.ApiVer + "-preview"
+}
+
+// approvalGap returns how many more approvals the strictest applicable minimum-reviewers
+// policy requires, or nil when no such policy applies to pr. Only people count: a group
+// reviewer votes whenever one of its members does. The gap is 0 only when every such
+// policy evaluated as approved, e.g. not when a rejection blocks it.
+func approvalGap(pr pullRequest, evals []policyEvaluation) *int {
+	required, found, creatorCounts, approved := 0, false, false, true
+	for _, ev := range evals {
+		c := ev.Configuration
+		isMinReviewers := strings.EqualFold(c.Type.ID, azdo.PolicyMinimumReviewers) ||
+			strings.EqualFold(c.Type.DisplayName, "Minimum number of reviewers")
+		if !isMinReviewers || !c.IsEnabled || strings.EqualFold(ev.Status, "notApplicable") {
+			continue
+		}
+		if c.Settings.MinimumApproverCount >= required {
+			required = c.Settings.MinimumApproverCount
+			creatorCounts = c.Settings.CreatorVoteCounts
+		}
+		approved = approved && strings.EqualFold(ev.Status, "approved")
+		found = true
+	}
+	if !found {
+		return nil
+	}
+	if approved {
+		gap := 0
+		return &gap
+	}
+	approvals := 0
+	for _, r := range pr.Reviewers {
+		if r.Vote < 5 || r.IsContainer {
+			continue
+		}
+		if !creatorCounts && pr.CreatedBy.ID != "" && r.ID == pr.CreatedBy.ID {
+			continue
+		}
+		approvals++
+	}
+	gap := max(1, required-approvals)
+	return &gap
+}
+
+// formatGap renders an approval gap: ✓ when satisfied, "–" when no policy applies.
+func formatGap(gap *int) string {
+	switch {
+	case gap == nil:
+		return "–"
+	case *gap == 0:
+		return "✓"
+	default:
+		return "+" + strconv.Itoa(*gap)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"LazyDevOps/pkg/azdo"
+)
+
+func minReviewersEval(status string, count int, creatorCounts bool) policyEvaluation {
+	var ev policyEvaluation
+	ev.Status = status
+	ev.Configuration.IsEnabled = true
+	ev.Configuration.Type.ID = azdo.PolicyMinimumReviewers
+	ev.Configuration.Settings.MinimumApproverCount = count
+	ev.Configuration.Settings.CreatorVoteCounts = creatorCounts
+	return ev
+}
+
+func TestApprovalGap(t *testing.T) {
+	author := identity{ID: "author"}
+	alice := reviewer{ID: "alice", Vote: 10}
+	bob := reviewer{ID: "bob", Vote: 5}
+	team := reviewer{ID: "team", Vote: 10, IsContainer: true}
+	authorVote := reviewer{ID: "author", Vote: 10}
+	rejected := reviewer{ID: "carol", Vote: -10}
+	var build policyEvaluation
+	build.Status = "rejected"
+	build.Configuration.IsEnabled = true
+	build.Configuration.Type.ID = "0609b952-1397-4640-95ec-e00a01b2c241"
+
+	for _, tc := range []struct {
+		name      string
+		reviewers []reviewer
+		evals     []policyEvaluation
+		want      int // -1 for no gap
+	}{
+		{"no policy", []reviewer{alice}, nil, -1},
+		{"other policies only", []reviewer{alice}, []policyEvaluation{build}, -1},
+		{"not applicable", []reviewer{alice}, []policyEvaluation{minReviewersEval("notApplicable", 2, false)}, -1},
+		{"approved", []reviewer{alice, bob}, []policyEvaluation{minReviewersEval("approved", 2, false)}, 0},
+		{"one missing", []reviewer{alice}, []policyEvaluation{minReviewersEval("running", 2, false)}, 1},
+		{"group vote does not count", []reviewer{alice, team}, []policyEvaluation{minReviewersEval("running", 2, false)}, 1},
+		{"author vote does not count", []reviewer{alice, authorVote}, []policyEvaluation{minReviewersEval("running", 2, false)}, 1},
+		{"author vote counts", []reviewer{alice, authorVote}, []policyEvaluation{minReviewersEval("approved", 2, true)}, 0},
+		{"rejection blocks", []reviewer{alice, bob, rejected}, []policyEvaluation{minReviewersEval("rejected", 2, false)}, 1},
+		{"strictest policy", []reviewer{alice}, []policyEvaluation{minReviewersEval("approved", 1, false), minReviewersEval("running", 3, false)}, 2},
+	} {
+		pr := pullRequest{CreatedBy: author, Reviewers: tc.reviewers}
+		got := approvalGap(pr, tc.evals)
+		switch {
+		case tc.want < 0 && got != nil:
+			t.Errorf("%s: approvalGap = %d, want none", tc.name, *got)
+		case tc.want >= 0 && (got == nil || *got != tc.want):
+			t.Errorf("%s: approvalGap = %v, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestFormatGap(t *testing.T) {
+	gap := func(n int) *int { return &n }
+	for _, tc := range []struct {
+		gap  *int
+		want string
+	}{
+		{nil, "–"},
+		{gap(0), "✓"},
+		{gap(1), "+1"},
+		{gap(3), "+3"},
+	} {
+		if got := formatGap(tc.gap); got != tc.want {
+			t.Errorf("formatGap(%v) = %q, want %q", tc.gap, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+)
+
+// adaptiveLimiter bounds the number of in-flight API calls. The limit starts at the
+// configured concurrency, halves (down to min) whenever the server throttles us and
+// grows by one (up to max) after a full window of unthrottled calls.
+type adaptiveLimiter struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    int
+	min, max int
+	inFlight int
+	streak   int
+}
+
+func newAdaptiveLimiter(start, min, max int) *adaptiveLimiter {
+	l := &adaptiveLimiter{limit: start, min: min, max: max}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *adaptiveLimiter) acquire() {
+	l.mu.Lock()
+	for l.inFlight >= l.limit {
+		l.cond.Wait()
+	}
+	l.inFlight++
+	l.mu.Unlock()
+}
+
+func (l *adaptiveLimiter) release(throttled bool) {
+	l.mu.Lock()
+	l.inFlight--
+	switch {
+	case throttled:
+		l.streak = 0
+		if l.limit > l.min {
+			l.limit = max(l.min, l.limit/2)
+			debugLog.Printf("throttled; concurrency lowered to %d", l.limit)
+		}
+	case l.limit < l.max:
+		l.streak++
+		if l.streak >= l.limit {
+			l.streak = 0
+			l.limit++
+			debugLog.Printf("concurrency raised to %d", l.limit)
+		}
+	}
+	l.mu.Unlock()
+	l.cond.Broadcast()
+}
+
+// do runs call under the limiter. Throttled requests are retried by the transport
+// (see azdo.RetryTransport); throttling of the requests call makes with ctx lowers the
+// limit, while throttling of concurrent calls does not count against this one. Calls
+// whose response was truncated are retried up to maxTruncatedRetries times.
+func (l *adaptiveLimiter) do(call func(ctx context.Context) error) error {
+	for truncations := 0; ; truncations++ {
+		l.acquire()
+		var throttles atomic.Int64
+		err := call(azdo.CountThrottles(context.Background(), &throttles))
+		var se *httpStatusError
+		throttled := throttles.Load() > 0 || (errors.As(err, &se) && se.Code == http.StatusTooManyRequests)
+		l.release(throttled)
+		if !isTruncated(err) || truncations >= maxTruncatedRetries {
+			return err
+		}
+		debugLog.Printf("response truncated (%v), retrying", err)
+		time.Sleep(truncatedRetryDelay << truncations)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+)
+
+// truncatingReader returns its data and then fails as if the connection dropped
+// mid-body.
+type truncatingReader struct {
+	r io.Reader
+}
+
+func (t *truncatingReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if err == io.EOF {
+		return n, io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func TestIsTruncated(t *testing.T) {
+	_, readErr := io.ReadAll(&truncatingReader{strings.NewReader(`{"value":[`)})
+	var v any
+	jsonErr := json.Unmarshal([]byte(`{"value":[`), &v)
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"cut body", readErr, true},
+		{"wrapped cut body", errors.Join(errors.New("reading response"), readErr), true},
+		{"cut json", jsonErr, true},
+		{"bad json", json.Unmarshal([]byte(`{"value":x}`), &v), false},
+		{"other", errors.New("HTTP 500"), false},
+		{"nil", nil, false},
+	} {
+		if got := isTruncated(tc.err); got != tc.want {
+			t.Errorf("%s: isTruncated(%v) = %v, want %v", tc.name, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestLimiterRetriesTruncatedResponses(t *testing.T) {
+	defer func(d time.Duration) { truncatedRetryDelay = d }(truncatedRetryDelay)
+	truncatedRetryDelay = time.Millisecond
+
+	fetch := func(truncated bool) error {
+		var r io.Reader = strings.NewReader(`{"value":[1,2]}`)
+		if truncated {
+			r = &truncatingReader{strings.NewReader(`{"value":[1,`)}
+		}
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		var v struct{ Value []int }
+		return json.Unmarshal(body, &v)
+	}
+
+	t.Run("recovers", func(t *testing.T) {
+		calls := 0
+		err := newAdaptiveLimiter(1, 1, 1).do(func(context.Context) error {
+			calls++
+			return fetch(calls == 1)
+		})
+		if err != nil || calls != 2 {
+			t.Errorf("do = %v after %d calls, want success after 2", err, calls)
+		}
+	})
+	t.Run("gives up", func(t *testing.T) {
+		calls := 0
+		err := newAdaptiveLimiter(1, 1, 1).do(func(context.Context) error {
+			calls++
+			return fetch(true)
+		})
+		if !isTruncated(err) || calls != maxTruncatedRetries+1 {
+			t.Errorf("do = %v after %d calls, want a truncation error after %d", err, calls, maxTruncatedRetries+1)
+		}
+	})
+	t.Run("other errors", func(t *testing.T) {
+		calls := 0
+		err := newAdaptiveLimiter(1, 1, 1).do(func(context.Context) error {
+			calls++
+			return errors.New("HTTP 404")
+		})
+		if err == nil || calls != 1 {
+			t.Errorf("do = %v after %d calls, want the error without a retry", err, calls)
+		}
+	})
+}
+
+func TestLimiterCountsOnlyOwnThrottling(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: azdo.RetryTransport{}}
+
+	lim := newAdaptiveLimiter(4, 1, 4)
+	throttled := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		lim.do(func(ctx context.Context) error {
+			// a body without GetBody is not replayed, so the 429 comes back at once
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, io.MultiReader(strings.NewReader("{}")))
+			resp, err := client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+			close(throttled)
+			return nil
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		lim.do(func(context.Context) error {
+			// still in flight when the other call is throttled
+			<-throttled
+			return nil
+		})
+	}()
+	wg.Wait()
+	if lim.limit != 2 {
+		t.Errorf("limit = %d after one throttled call, want 2", lim.limit)
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Reviewer vote values accepted by the pullRequestReviewers API, keyed by --vote name.
+var voteValues = map[string]int{
+	"approve":                  10,
+	"approve-with-suggestions": 5,
+	"reset":                    0,
+	"wait":                     voteWaitingForAuthor,
+	"reject":                   -10,
+}
+
+// fetchPR loads a single pull request of the project by ID. With several projects
+// configured it is looked up in the whole organization.
+func fetchPR(cfg config, id int) (pullRequest, error) {
+	project := cfg.Project
+	if cfg.multiProject() {
+		project = ""
+	}
+	pr, err := apiClient(cfg, 15*time.Second).PullRequest(context.Background(), project, id)
+	if err != nil {
+		return pr, fmt.Errorf("loading PR %d: %w", id, err)
+	}
+	return pr, nil
+}
+
+// prEndpoint returns the URL of pr's resource (or of path below it) in its repository.
+func prEndpoint(cfg config, pr pullRequest, path string) string {
+	return apiClient(cfg, 0).PullRequestURL(prProject(cfg, pr), pr, path)
+}
+
+// setVote records me's vote on pr, adding me as a reviewer if needed.
+func setVote(cfg config, pr pullRequest, me userIdentity, vote int) error {
+	endpoint := prEndpoint(cfg, pr, "/reviewers/"+url.PathEscape(me.ID))
+	return apiSend(cfg, http.MethodPut, endpoint, map[string]any{"vote": vote}, nil)
+}
+
+// parsePRID parses a pull request ID given as "123" or "#123".
+func parsePRID(s string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(s, "#"))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid pull request ID %q", s)
+	}
+	return id, nil
+}
+
+// prArg returns the single PR ID a command like "pr approve <id>" was given.
+func prArg(cfg config, command string) int {
+	if len(cfg.Args) != 1 {
+		failUsage(fmt.Sprintf("usage: lazydevops pr %s <id> [flags]", command))
+	}
+	id, err := parsePRID(cfg.Args[0])
+	if err != nil {
+		failUsage(err.Error())
+	}
+	return id
+}
+
+// runPRApprove runs "pr approve <id>": it sets the caller's vote, approve by default.
+func runPRApprove(args []string) {
+	vote := flag.String("vote", "approve", "Vote to cast: approve, approve-with-suggestions, wait (for author), reject or reset")
+	cfg := getConfig(args)
+	id := prArg(cfg, "approve")
+	value, ok := voteValues[strings.ToLower(*vote)]
+	if !ok {
+		failUsage("--vote must be one of: approve, approve-with-suggestions, wait, reject, reset")
+	}
+
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	me, err := resolveMe(cfg)
+	if err != nil {
+		log.Fatalln("Error: resolving your identity:", err)
+	}
+	if err := setVote(cfg, pr, me, value); err != nil {
+		log.Fatalln("Error: voting:", err)
+	}
+	fmt.Printf("Voted %s on PR #%d: %s\n", strings.ToLower(*vote), pr.PullRequestID, pr.Title)
+}
+
+// prWebURL is the web page of pull request id in repository repo.
+func prWebURL(cfg config, repo string, id int) string {
+	return apiClient(cfg, 0).WebURL(cfg.Project, fmt.Sprintf("_git/%s/pullrequest/%d", url.PathEscape(repo), id))
+}
+
+// runPRCreate runs "pr create": it opens a pull request from the checked-out branch of
+// the repository in the current directory, into --target or the default branch.
+func runPRCreate(args []string) {
+	title := flag.String("title", "", "Title of the pull request (default: the last commit's subject)")
+	description := flag.String("description", "", "Description of the pull request")
+	draft := flag.Bool("draft", false, "Create the pull request as a draft")
+	cfg := getConfig(args)
+	if len(cfg.Args) > 0 {
+		failUsage("usage: lazydevops pr create [--title T] [--description D] [--target branch] [--draft]")
+	}
+
+	remote, err := originRemote(cfg.BaseURL)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	if !strings.EqualFold(remote.Org, cfg.Org) || !strings.EqualFold(remote.Project, cfg.Project) {
+		fmt.Fprintf(os.Stderr, "Warning: origin points to %s/%s, creating the PR in %s/%s\n", remote.Org, remote.Project, cfg.Org, cfg.Project)
+	}
+	source, err := currentBranch()
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	repo, err := (&repoIndex{}).lookup(cfg, remote.Repo)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	target := repo.DefaultBranch
+	if cfg.Target != "" {
+		target = branchRef(cfg.Target)
+	}
+	if target == "" {
+		failUsage("--target is required: the repository has no default branch")
+	}
+	if strings.EqualFold(source, target) {
+		failUsage("the checked-out branch " + refShort(source) + " is the target branch; switch to a feature branch first")
+	}
+
+	if *title == "" {
+		def, _ := git("log", "-1", "--format=%s")
+		*title = def
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			in := bufio.NewReader(os.Stdin)
+			*title = prompt(in, "Title", def)
+			if *description == "" {
+				*description = prompt(in, "Description", "")
+			}
+		}
+	}
+	if *title == "" {
+		failUsage("--title is required")
+	}
+
+	endpoint := apiClient(cfg, 0).URL(cfg.Project, "_apis/git/repositories/"+url.PathEscape(repo.ID)+"/pullrequests", nil)
+	body := map[string]any{
+		"sourceRefName": source,
+		"targetRefName": target,
+		"title":         *title,
+		"description":   *description,
+		"isDraft":       *draft,
+	}
+	var pr pullRequest
+	if err := apiSend(cfg, http.MethodPost, endpoint, body, &pr); err != nil {
+		log.Fatalln("Error: creating the pull request:", err)
+	}
+	fmt.Printf("Created PR #%d: %s (%s -> %s)\n", pr.PullRequestID, pr.Title, refShort(source), refShort(target))
+	fmt.Println(prWebURL(cfg, repo.Name, pr.PullRequestID))
+}
+
+// mergeStrategies maps --strategy names to the API's GitPullRequestMergeStrategy values.
+var mergeStrategies = map[string]string{
+	"merge":        "noFastForward",
+	"squash":       "squash",
+	"rebase":       "rebase",
+	"rebase-merge": "rebaseMerge",
+}
+
+// updatePR PATCHes fields of pr and returns the updated pull request.
+func updatePR(cfg config, pr pullRequest, fields map[string]any) (pullRequest, error) {
+	var updated pullRequest
+	err := apiSend(cfg, http.MethodPatch, prEndpoint(cfg, pr, ""), fields, &updated)
+	return updated, err
+}
+
+// runPRComplete runs "pr complete <id>": it merges the PR with the chosen strategy
+// after asking for confirmation.
+func runPRComplete(args []string) {
+	strategy := flag.String("strategy", "squash", "Merge strategy: squash, rebase, merge (no fast-forward) or rebase-merge")
+	deleteSource := flag.Bool("delete-source", false, "Delete the source branch after merging")
+	yes := flag.Bool("yes", false, "Do not ask for confirmation")
+	cfg := getConfig(args)
+	id := prArg(cfg, "complete")
+	apiStrategy, ok := mergeStrategies[strings.ToLower(*strategy)]
+	if !ok {
+		failUsage("--strategy must be one of: squash, rebase, merge, rebase-merge")
+	}
+
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	if !strings.EqualFold(pr.Status, "active") {
+		log.Fatalf("Error: PR #%d is %s, only active PRs can be completed\n", id, pr.Status)
+	}
+	if pr.LastMergeSourceCommit == nil {
+		log.Fatalf("Error: PR #%d has no merge source commit yet; try again in a moment\n", id)
+	}
+	question := fmt.Sprintf("Complete PR #%d %q (%s into %s, %s)?", id, pr.Title, refShort(pr.SourceRefName), refShort(pr.TargetRefName), strings.ToLower(*strategy))
+	if !*yes && !confirm(question) {
+		fmt.Println("Aborted.")
+		return
+	}
+
+	updated, err := updatePR(cfg, pr, map[string]any{
+		"status":                "completed",
+		"lastMergeSourceCommit": pr.LastMergeSourceCommit,
+		"completionOptions": map[string]any{
+			"mergeStrategy":      apiStrategy,
+			"deleteSourceBranch": *deleteSource,
+		},
+	})
+	if err != nil {
+		log.Fatalln("Error: completing the pull request:", err)
+	}
+	if strings.EqualFold(updated.Status, "completed") {
+		fmt.Printf("Completed PR #%d: %s\n", id, pr.Title)
+		return
+	}
+	// Policies or a running merge can leave the PR active; the server then completes it later.
+	fmt.Printf("Completion of PR #%d requested; it is still %s (pending merge or policies)\n", id, updated.Status)
+}
+
+// setPRStatus returns a command that moves a PR from status from to status to,
+// e.g. "pr abandon" (active → abandoned) and "pr reactivate" (abandoned → active).
+func setPRStatus(command, from, to string) func(args []string) {
+	return func(args []string) {
+		cfg := getConfig(args)
+		id := prArg(cfg, command)
+		pr, err := fetchPR(cfg, id)
+		if err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		if !strings.EqualFold(pr.Status, from) {
+			log.Fatalf("Error: PR #%d is %s, only %s PRs can be set to %s\n", id, pr.Status, from, to)
+		}
+		if _, err := updatePR(cfg, pr, map[string]any{"status": to}); err != nil {
+			log.Fatalln("Error: updating the pull request:", err)
+		}
+		fmt.Printf("PR #%d is now %s: %s\n", id, to, pr.Title)
+	}
+}
+
+// runPROpen runs "pr open <id>": it opens the pull request's web page in the default browser.
+func runPROpen(args []string) {
+	cfg := getConfig(args)
+	id := prArg(cfg, "open")
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	href := pr.Links.Web.Href
+	if href == "" {
+		href = prWebURL(cfg, pr.Repository.Name, id)
+	}
+	fmt.Println("Opening", href)
+	if err := openBrowser(href); err != nil {
+		log.Fatalln("Error: could not open browser:", err)
+	}
+}
+
+// runPRAutocomplete runs "pr autocomplete <id>": it sets auto-complete as the caller, so
+// the PR merges once its policies pass, or cancels it with --off.
+func runPRAutocomplete(args []string) {
+	off := flag.Bool("off", false, "Cancel auto-complete")
+	strategy := flag.String("strategy", "squash", "Merge strategy: squash, rebase, merge (no fast-forward) or rebase-merge")
+	deleteSource := flag.Bool("delete-source", false, "Delete the source branch after merging")
+	cfg := getConfig(args)
+	id := prArg(cfg, "autocomplete")
+	apiStrategy, ok := mergeStrategies[strings.ToLower(*strategy)]
+	if !ok {
+		failUsage("--strategy must be one of: squash, rebase, merge, rebase-merge")
+	}
+
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	if !strings.EqualFold(pr.Status, "active") {
+		log.Fatalf("Error: PR #%d is %s, auto-complete only applies to active PRs\n", id, pr.Status)
+	}
+	if *off {
+		// The API cancels auto-complete when it is set by the empty identity.
+		fields := map[string]any{"autoCompleteSetBy": map[string]string{"id": "00000000-0000-0000-0000-000000000000"}}
+		if _, err := updatePR(cfg, pr, fields); err != nil {
+			log.Fatalln("Error: canceling auto-complete:", err)
+		}
+		fmt.Printf("Canceled auto-complete of PR #%d: %s\n", id, pr.Title)
+		return
+	}
+	me, err := resolveMe(cfg)
+	if err != nil {
+		log.Fatalln("Error: resolving your identity:", err)
+	}
+	_, err = updatePR(cfg, pr, map[string]any{
+		"autoCompleteSetBy": map[string]string{"id": me.ID},
+		"completionOptions": map[string]any{
+			"mergeStrategy":      apiStrategy,
+			"deleteSourceBranch": *deleteSource,
+		},
+	})
+	if err != nil {
+		log.Fatalln("Error: setting auto-complete:", err)
+	}
+	fmt.Printf("Set auto-complete (%s) on PR #%d: %s\n", strings.ToLower(*strategy), id, pr.Title)
+}
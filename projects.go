@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// allProjects is the --project value that selects every project of the organization.
+const allProjects = "*"
+
+// multiProject reports whether the PR list spans several projects. A list spanning
+// several organizations counts as such too, since their projects differ.
+func (cfg config) multiProject() bool {
+	return len(cfg.Projects) > 1 || cfg.Project == allProjects || cfg.multiOrg()
+}
+
+// fetchProjectNames lists the names of the organization's projects.
+func fetchProjectNames(cfg config) ([]string, error) {
+	projects, err := apiClient(cfg, 15*time.Second).Projects(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(projects))
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+// selectedProjects resolves --project to the projects to list PRs of.
+func selectedProjects(cfg config) ([]string, error) {
+	if cfg.Project != allProjects {
+		return cfg.Projects, nil
+	}
+	names, err := fetchProjectNames(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+	return names, nil
+}
+
+// prProject is the project pr belongs to, for per-PR endpoints: the PR's own project
+// when the listing reported it, else the configured one.
+func prProject(cfg config, pr pullRequest) string {
+	switch p := pr.Repository.Project; {
+	case p.Name != "":
+		return p.Name
+	case p.ID != "":
+		return p.ID
+	}
+	return cfg.Project
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// repoSummary is a repository as listed by "repos list".
+type repoSummary struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	DefaultBranch string    `json:"defaultBranch,omitempty"`
+	Size          int64     `json:"size"`
+	LastPush      time.Time `json:"lastPush,omitzero"`
+	Disabled      bool      `json:"disabled,omitempty"`
+	URL           string    `json:"url"`
+}
+
+// fetchLastPushes sets the LastPush of each enabled repository, calling the pushes API
+// concurrently. Failed calls leave it zero and are returned joined by repository.
+func fetchLastPushes(cfg config, repos []repoSummary) error {
+	c := apiClient(cfg, 15*time.Second)
+	lim := newAdaptiveLimiter(cfg.Concurrency, cfg.MinConcurrency, cfg.MaxConcurrency)
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []string
+	)
+	for i := range repos {
+		if repos[i].Disabled {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := lim.do(func(ctx context.Context) error {
+				var err error
+				repos[i].LastPush, err = c.LastPush(ctx, cfg.Project, repos[i].ID)
+				return err
+			})
+			if err != nil {
+				mu.Lock()
+				failed = append(failed, fmt.Sprintf("%s: %v", repos[i].Name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("loading last pushes: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// printRepos writes one row per repository.
+func printRepos(w io.Writer, cfg config, repos []repoSummary) {
+	th := resolveTheme(cfg)
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(th.Style)
+	t.SetTitle(fmt.Sprintf("Repositories in %s", cfg.Project))
+	t.AppendHeader(table.Row{"Name", "Default branch", "Size", "Last push", "URL"})
+	for _, r := range repos {
+		name, push := r.Name, relTime(cfg, r.LastPush)
+		switch {
+		case r.Disabled:
+			name += " (disabled)"
+			push = ""
+		case r.LastPush.IsZero():
+			push = "never"
+		}
+		branch := ""
+		if r.DefaultBranch != "" {
+			branch = refShort(r.DefaultBranch)
+		}
+		t.AppendRow(table.Row{name, branch, humanize.IBytes(uint64(r.Size)), push, r.URL})
+	}
+	t.Render()
+}
+
+// runReposList runs "repos list": the project's repositories with their default
+// branch, size and last push, for inventory and cleanup.
+func runReposList(args []string) {
+	cfg := getConfig(args)
+	if cfg.multiProject() {
+		failUsage("repos list needs a single --project")
+	}
+	all, err := fetchRepositories(cfg)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	repos := make([]repoSummary, 0, len(all))
+	for _, r := range all {
+		repos = append(repos, repoSummary{
+			ID:            r.ID,
+			Name:          r.Name,
+			DefaultBranch: r.DefaultBranch,
+			Size:          r.Size,
+			Disabled:      r.IsDisabled,
+			URL:           r.WebURL,
+		})
+	}
+	sort.Slice(repos, func(i, j int) bool { return strings.ToLower(repos[i].Name) < strings.ToLower(repos[j].Name) })
+	if err := fetchLastPushes(cfg, repos); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
+
+	out, closeOut := openOutput(cfg)
+	defer closeOut()
+	switch {
+	case cfg.Output == outputJSON:
+		if err := printJSON(out, orEmpty(repos)); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case len(repos) == 0:
+		fmt.Fprintf(out, "No repositories in %s.\n", cfg.Project)
+	default:
+		printRepos(out, cfg, repos)
+	}
+}
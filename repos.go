@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+)
+
+// repository is a git repository as returned by the repositories API.
+type repository = azdo.Repository
+
+// fetchRepositories lists the git repositories of the project.
+func fetchRepositories(cfg config) ([]repository, error) {
+	return apiClient(cfg, 15*time.Second).Repositories(context.Background(), cfg.Project)
+}
+
+// repoIndex loads the project's repositories on first use and resolves them by name.
+// It is safe for concurrent use.
+type repoIndex struct {
+	once   sync.Once
+	byName map[string]repository
+	err    error
+}
+
+func (ix *repoIndex) load(cfg config) error {
+	ix.once.Do(func() {
+		repos, err := fetchRepositories(cfg)
+		if err != nil {
+			ix.err = err
+			return
+		}
+		ix.byName = make(map[string]repository, 2*len(repos))
+		for _, r := range repos {
+			ix.byName[strings.ToLower(r.Name)] = r
+			ix.byName[strings.ToLower(r.ID)] = r
+		}
+	})
+	return ix.err
+}
+
+// lookup finds a repository by (case-insensitive) name or ID.
+func (ix *repoIndex) lookup(cfg config, name string) (repository, error) {
+	if name == "" {
+		return repository{}, fmt.Errorf("repository name is empty")
+	}
+	if err := ix.load(cfg); err != nil {
+		return repository{}, err
+	}
+	r, ok := ix.byName[strings.ToLower(name)]
+	if !ok || r.ID == "" {
+		return repository{}, fmt.Errorf("repository %q not found", name)
+	}
+	return r, nil
+}
+
+// projectRepos holds a repoIndex per organization and project, for PR lists spanning
+// several of them. It is safe for concurrent use.
+type projectRepos struct {
+	mu sync.Mutex
+	m  map[string]*repoIndex
+}
+
+// of returns the index of the repositories of cfg's organization and project.
+func (p *projectRepos) of(cfg config) *repoIndex {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := strings.ToLower(cfg.Org + "/" + cfg.Project)
+	if p.m[key] == nil {
+		if p.m == nil {
+			p.m = map[string]*repoIndex{}
+		}
+		p.m[key] = &repoIndex{}
+	}
+	return p.m[key]
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// findIdentity resolves a user given by email, account or display name through the
+// identities API. "me" is the authenticated user. Ambiguous names are an error listing
+// the candidates.
+func findIdentity(cfg config, user string) (identity, error) {
+	if strings.EqualFold(user, "me") {
+		me, err := resolveMe(cfg)
+		if err != nil {
+			return identity{}, err
+		}
+		return identity{ID: me.ID, DisplayName: me.DisplayName, UniqueName: me.UniqueName}, nil
+	}
+	q := url.Values{}
+	q.Set("searchFilter", "General")
+	q.Set("filterValue", user)
+	q.Set("queryMembership", "None")
+	endpoint := apiClient(cfg, 0).IdentityURL("_apis/identities", q)
+	var resp struct {
+		Value []struct {
+			ID                  string `json:"id"`
+			ProviderDisplayName string `json:"providerDisplayName"`
+			Properties          struct {
+				Account struct {
+					Value string `json:"$value"`
+				} `json:"Account"`
+			} `json:"properties"`
+		} `json:"value"`
+	}
+	if err := apiGet(cfg, endpoint, &resp); err != nil {
+		return identity{}, fmt.Errorf("looking up %q: %w", user, err)
+	}
+	switch len(resp.Value) {
+	case 0:
+		return identity{}, fmt.Errorf("no user or group matches %q", user)
+	case 1:
+		v := resp.Value[0]
+		return identity{ID: v.ID, DisplayName: v.ProviderDisplayName, UniqueName: v.Properties.Account.Value}, nil
+	}
+	names := make([]string, 0, len(resp.Value))
+	for _, v := range resp.Value {
+		names = append(names, fmt.Sprintf("%s <%s>", v.ProviderDisplayName, v.Properties.Account.Value))
+	}
+	return identity{}, fmt.Errorf("%q matches several identities, use the email address: %s", user, strings.Join(names, ", "))
+}
+
+// identityNames resolves identity IDs, e.g. the required reviewers of a policy, to
+// display names. IDs that cannot be resolved are missing from the result.
+func identityNames(cfg config, ids []string) (map[string]string, error) {
+	names := map[string]string{}
+	if len(ids) == 0 {
+		return names, nil
+	}
+	q := url.Values{}
+	q.Set("identityIds", strings.Join(ids, ","))
+	q.Set("queryMembership", "None")
+	var resp struct {
+		Value []struct {
+			ID                  string `json:"id"`
+			ProviderDisplayName string `json:"providerDisplayName"`
+		} `json:"value"`
+	}
+	if err := apiGet(cfg, apiClient(cfg, 0).IdentityURL("_apis/identities", q), &resp); err != nil {
+		return nil, err
+	}
+	for _, v := range resp.Value {
+		// unknown IDs come back as null entries
+		if v.ID != "" {
+			names[strings.ToLower(v.ID)] = v.ProviderDisplayName
+		}
+	}
+	return names, nil
+}
+
+// reviewerArgs parses the PR ID and resolves the --user values of "pr reviewers add/remove".
+func reviewerArgs(cfg config, command string, users []string) (pullRequest, []identity) {
+	id := prArg(cfg, "reviewers "+command)
+	if len(users) == 0 {
+		failUsage("--user is required")
+	}
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	ids := make([]identity, 0, len(users))
+	for _, u := range users {
+		ident, err := findIdentity(cfg, u)
+		if err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		ids = append(ids, ident)
+	}
+	return pr, ids
+}
+
+// runPRReviewersAdd runs "pr reviewers add <id> --user U": it adds reviewers without a
+// vote, optionally as required reviewers. Reviewers already on the PR keep their vote;
+// with --required, optional ones are made required.
+func runPRReviewersAdd(args []string) {
+	var users stringList
+	flag.Var(&users, "user", "Reviewer to add by email, account or display name (repeatable or comma-separated; \"me\" is you)")
+	required := flag.Bool("required", false, "Add the reviewers as required reviewers")
+	cfg := getConfig(args)
+	pr, ids := reviewerArgs(cfg, "add", users)
+	for _, ident := range ids {
+		i := slices.IndexFunc(pr.Reviewers, func(r reviewer) bool { return strings.EqualFold(r.ID, ident.ID) })
+		if i >= 0 && (pr.Reviewers[i].IsRequired || !*required) {
+			kind := "a reviewer"
+			if pr.Reviewers[i].IsRequired {
+				kind = "a required reviewer"
+			}
+			fmt.Printf("%s is already %s of PR #%d\n", ident.DisplayName, kind, pr.PullRequestID)
+			continue
+		}
+		// no vote in the body: the server keeps the vote of an existing reviewer
+		endpoint := prEndpoint(cfg, pr, "/reviewers/"+url.PathEscape(ident.ID))
+		if err := apiSend(cfg, http.MethodPut, endpoint, map[string]any{"isRequired": *required}, nil); err != nil {
+			log.Fatalf("Error: adding %s: %v\n", ident.DisplayName, err)
+		}
+		switch {
+		case i >= 0:
+			fmt.Printf("Made %s a required reviewer of PR #%d\n", ident.DisplayName, pr.PullRequestID)
+		case *required:
+			fmt.Printf("Added %s as required reviewer to PR #%d\n", ident.DisplayName, pr.PullRequestID)
+		default:
+			fmt.Printf("Added %s as reviewer to PR #%d\n", ident.DisplayName, pr.PullRequestID)
+		}
+	}
+}
+
+// runPRReviewersRemove runs "pr reviewers remove <id> --user U".
+func runPRReviewersRemove(args []string) {
+	var users stringList
+	flag.Var(&users, "user", "Reviewer to remove by email, account or display name (repeatable or comma-separated; \"me\" is you)")
+	cfg := getConfig(args)
+	pr, ids := reviewerArgs(cfg, "remove", users)
+	for _, ident := range ids {
+		endpoint := prEndpoint(cfg, pr, "/reviewers/"+url.PathEscape(ident.ID))
+		if err := apiSend(cfg, http.MethodDelete, endpoint, nil, nil); err != nil {
+			log.Fatalf("Error: removing %s: %v\n", ident.DisplayName, err)
+		}
+		fmt.Printf("Removed %s from the reviewers of PR #%d\n", ident.DisplayName, pr.PullRequestID)
+	}
+}
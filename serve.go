@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultServeInterval is how often "serve" refreshes the PRs by default.
+const defaultServeInterval = time.Minute
+
+// checkClasses maps check words to the CSS classes of the dashboard.
+var checkClasses = map[string]string{
+	"Passed":       "ok",
+	"Failed":       "bad",
+	"Unauthorized": "bad",
+	"In Progress":  "busy",
+	"Stuck?":       "busy",
+}
+
+// dashboardPage is the HTML of the dashboard; it reloads itself every Refresh seconds.
+var dashboardPage = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"ref":   refShort,
+	"votes": summarizeVotesTyped,
+	"checkClass": func(checks string) string {
+		if c, ok := checkClasses[checks]; ok {
+			return c
+		}
+		return "na"
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 1.5em; background: #111; color: #ddd; }
+h1 { font-size: 1.4em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .35em .6em; border-bottom: 1px solid #333; }
+th { color: #999; font-weight: normal; }
+a { color: #7ab7ff; text-decoration: none; }
+.ok { color: #5c5; } .bad { color: #f55; font-weight: bold; } .busy { color: #fc5; } .na { color: #999; }
+.meta { color: #999; font-size: .9em; } .error { color: #f55; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Summary}} · updated {{.Updated}}{{if .Error}} · <span class="error">last refresh failed: {{.Error}}</span>{{end}}</p>
+<table>
+<tr><th>PR</th><th>Title</th><th>Author</th><th>Repo</th><th>Source→Target</th><th>Votes</th><th>Checks</th><th>Created</th></tr>
+{{range .PRs}}<tr>
+<td>{{.PullRequestID}}</td>
+<td><a href="{{.Links.Web.Href}}">{{if .IsDraft}}[draft] {{end}}{{.Title}}</a></td>
+<td>{{.CreatedBy.DisplayName}}</td>
+<td>{{.Repository.Name}}</td>
+<td>{{ref .SourceRefName}} → {{ref .TargetRefName}}</td>
+<td>{{votes .Reviewers}}</td>
+<td class="{{checkClass .Checks}}">{{.Checks}}</td>
+<td>{{.Created}}</td>
+</tr>{{end}}
+</table>
+</body>
+</html>
+`))
+
+// dashboardPR is a row of the dashboard.
+type dashboardPR struct {
+	prRecord
+	Created string
+}
+
+// dashboard holds the latest PRs for the handlers of "serve". It is safe for
+// concurrent use.
+type dashboard struct {
+	cfg      config
+	interval time.Duration
+
+	mu      sync.RWMutex
+	recs    []prRecord
+	updated time.Time
+	err     error
+}
+
+// refresh fetches and enriches the PRs like a normal run. A failed refresh keeps the
+// previous PRs and records the error.
+func (d *dashboard) refresh() {
+	prs, me, err := fetchSelected(d.cfg)
+	var recs []prRecord
+	if err == nil {
+		errs := newEnrichErrors()
+		recs = finishRecords(d.cfg, prs, me, errs)
+		if errs.len() > 0 {
+			debugLog.Printf("serve: %d enrichment calls failed", errs.len())
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.err = err
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: refreshing:", err)
+		return
+	}
+	d.recs, d.updated = recs, time.Now()
+}
+
+// loop refreshes the PRs every interval, forever.
+func (d *dashboard) loop() {
+	for {
+		time.Sleep(d.interval)
+		d.refresh()
+	}
+}
+
+// snapshot returns the latest PRs, when they were loaded and the last refresh error.
+func (d *dashboard) snapshot() ([]prRecord, time.Time, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.recs, d.updated, d.err
+}
+
+// serveHTML renders the dashboard.
+func (d *dashboard) serveHTML(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	recs, updated, err := d.snapshot()
+	title := "Pull requests"
+	if len(d.cfg.Projects) > 0 {
+		title += " in " + strings.Join(d.cfg.Projects, ", ")
+	}
+	rows := make([]dashboardPR, 0, len(recs))
+	for _, rec := range recs {
+		rows = append(rows, dashboardPR{prRecord: rec, Created: relTime(d.cfg, rec.CreationDate.Time)})
+	}
+	data := struct {
+		Title, Summary, Updated, Error string
+		Refresh                        int
+		PRs                            []dashboardPR
+	}{
+		Title:   title,
+		Summary: summaryLine(recs),
+		Updated: relTime(d.cfg, updated),
+		Refresh: max(5, int(d.interval.Seconds())),
+		PRs:     rows,
+	}
+	if err != nil {
+		data.Error = err.Error()
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardPage.Execute(w, data); err != nil {
+		debugLog.Printf("serve: rendering the dashboard: %v", err)
+	}
+}
+
+// serveJSON writes the latest PRs as the JSON array of --output json, with the time
+// they were loaded as Last-Modified.
+func (d *dashboard) serveJSON(w http.ResponseWriter, r *http.Request) {
+	recs, updated, _ := d.snapshot()
+	if recs == nil {
+		recs = []prRecord{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !updated.IsZero() {
+		w.Header().Set("Last-Modified", updated.UTC().Format(http.TimeFormat))
+	}
+	if err := printJSON(w, recs); err != nil {
+		debugLog.Printf("serve: writing JSON: %v", err)
+	}
+}
+
+// runServe runs "serve": it refreshes the PRs periodically and serves an auto-refreshing
+// HTML dashboard at / and the PRs as JSON at /api/prs.
+func runServe(args []string) {
+	listen := flag.String("listen", "127.0.0.1:8080", "Address to serve the dashboard on; only this machine by default, use e.g. :8080 to expose it")
+	interval := flag.String("interval", defaultServeInterval.String(), "How often to refresh the PRs, at least 5s")
+	cfg := getConfig(args)
+	if len(cfg.Args) > 0 {
+		failUsage("unexpected argument " + cfg.Args[0])
+	}
+	every, err := parseAge(*interval)
+	if err != nil || every < 5*time.Second {
+		failUsage("--interval must be a duration of at least 5s, e.g. 30s or 5m")
+	}
+
+	d := &dashboard{cfg: cfg, interval: every}
+	d.refresh()
+	go d.loop()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", d.serveHTML)
+	mux.HandleFunc("/api/prs", d.serveJSON)
+	srv := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	fmt.Printf("Serving the PR dashboard on %s (refreshing every %s). Press Ctrl+C to quit.\n", *listen, every)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalln("Error: ", err)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prDetails is everything "pr show" prints about a pull request. Parts that could not
+// be loaded are left empty and their error is kept in Errors.
+type prDetails struct {
+	pullRequest
+	WorkItems       []string          `json:"workItems"`
+	WorkItemDetails []workItem        `json:"workItemDetails,omitempty"`
+	Iterations      int               `json:"iterations"`
+	ActiveThreads   int               `json:"activeThreads"`
+	ResolvedThreads int               `json:"resolvedThreads"`
+	Checks          []prCheck         `json:"checks"`
+	Errors          map[string]string `json:"errors,omitempty"`
+}
+
+// fetchIterationCount returns how many iterations (pushes) pr has.
+func fetchIterationCount(cfg config, pr pullRequest) (int, error) {
+	var resp struct {
+		Count int `json:"count"`
+	}
+	if err := apiGet(cfg, prEndpoint(cfg, pr, "/iterations"), &resp); err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// fetchWorkItemDetails loads the type, state, title and assignee of the work items ids
+// linked to pr.
+func fetchWorkItemDetails(cfg config, pr pullRequest, ids []string) ([]workItem, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	nums := make([]int, 0, len(ids))
+	for _, id := range ids {
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("work item ID %q: %w", id, err)
+		}
+		nums = append(nums, n)
+	}
+	wis, err := apiClient(cfg, 15*time.Second).WorkItems(context.Background(), prProject(cfg, pr), nums, workItemFields)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]workItem, 0, len(wis))
+	for _, wi := range wis {
+		items = append(items, newWorkItem(cfg, wi))
+	}
+	return items, nil
+}
+
+// loadPRDetails gathers the details of pr, with up to logLines lines of the logs of
+// failed build tasks. A failing call only leaves its part empty.
+func loadPRDetails(cfg config, pr pullRequest, logLines int) prDetails {
+	d := prDetails{pullRequest: pr, Errors: map[string]string{}}
+	var err error
+	if d.WorkItems, err = fetchPRWorkItems(context.Background(), cfg, pr); err != nil {
+		d.Errors["workItems"] = err.Error()
+	} else if d.WorkItemDetails, err = fetchWorkItemDetails(cfg, pr, d.WorkItems); err != nil {
+		d.Errors["workItemDetails"] = err.Error()
+	}
+	if d.Iterations, err = fetchIterationCount(cfg, pr); err != nil {
+		d.Errors["iterations"] = err.Error()
+	}
+	if threads, err := fetchThreads(cfg, pr); err != nil {
+		d.Errors["threads"] = err.Error()
+	} else {
+		for _, t := range threads {
+			if t.isActive() {
+				d.ActiveThreads++
+			} else {
+				d.ResolvedThreads++
+			}
+		}
+	}
+	if d.Checks, err = prChecks(cfg, pr); err != nil {
+		d.Errors["checks"] = err.Error()
+	} else if err := addBuildFailures(cfg, pr, d.Checks, logLines); err != nil {
+		d.Errors["buildFailures"] = err.Error()
+	}
+	return d
+}
+
+// printPRDetails writes d as labeled sections.
+func printPRDetails(w io.Writer, cfg config, d prDetails) {
+	th := resolveTheme(cfg)
+	unavailable := func(part string) string { return "unavailable (" + d.Errors[part] + ")" }
+
+	fmt.Fprintf(w, "#%d %s\n", d.PullRequestID, d.Title)
+	status := d.Status
+	if d.IsDraft {
+		status += ", draft"
+	}
+	fmt.Fprintf(w, "Status:     %s\n", status)
+	fmt.Fprintf(w, "Author:     %s\n", d.CreatedBy.DisplayName)
+	created := relTime(cfg, d.CreationDate.Time)
+	if !d.CreationDate.IsZero() {
+		created = absTime(cfg, d.CreationDate.Time) + " (" + created + ")"
+	}
+	fmt.Fprintf(w, "Created:    %s\n", created)
+	fmt.Fprintf(w, "Repo:       %s\n", d.Repository.Name)
+	fmt.Fprintf(w, "Branches:   %s -> %s\n", refShort(d.SourceRefName), refShort(d.TargetRefName))
+	merge := d.MergeStatus
+	if merge == "" {
+		merge = "unknown"
+	}
+	fmt.Fprintf(w, "Merge:      %s\n", merge)
+	if _, failed := d.Errors["iterations"]; failed {
+		fmt.Fprintf(w, "Iterations: %s\n", unavailable("iterations"))
+	} else {
+		fmt.Fprintf(w, "Iterations: %d\n", d.Iterations)
+	}
+	if _, failed := d.Errors["threads"]; failed {
+		fmt.Fprintf(w, "Threads:    %s\n", unavailable("threads"))
+	} else {
+		fmt.Fprintf(w, "Threads:    %d active, %d resolved\n", d.ActiveThreads, d.ResolvedThreads)
+	}
+	switch _, failed := d.Errors["workItems"]; {
+	case failed:
+		fmt.Fprintf(w, "Work items: %s\n", unavailable("workItems"))
+	case len(d.WorkItems) == 0:
+		fmt.Fprintln(w, "Work items: none")
+	case len(d.WorkItemDetails) == 0:
+		fmt.Fprintf(w, "Work items: #%s\n", strings.Join(d.WorkItems, ", #"))
+	default:
+		fmt.Fprintln(w, "Work items:")
+		for _, wi := range d.WorkItemDetails {
+			line := fmt.Sprintf("  #%d %s, %s: %s", wi.ID, wi.Type, wi.State, wi.Title)
+			if wi.AssignedTo != "" {
+				line += " (" + wi.AssignedTo + ")"
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+	if d.Links.Web.Href != "" {
+		fmt.Fprintf(w, "URL:        %s\n", d.Links.Web.Href)
+	}
+
+	fmt.Fprintf(w, "\nReviewers (%s):\n", summarizeVotesTyped(d.Reviewers))
+	if len(d.Reviewers) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, rv := range d.Reviewers {
+		label := voteLabel(rv)
+		if rv.IsRequired {
+			label += ", required"
+		}
+		if rv.IsFlagged {
+			label += ", flagged"
+		}
+		fmt.Fprintf(w, "  %s (%s)\n", rv.DisplayName, label)
+	}
+
+	fmt.Fprintln(w, "\nChecks:")
+	switch _, failed := d.Errors["checks"]; {
+	case failed:
+		fmt.Fprintf(w, "  %s\n", unavailable("checks"))
+	case len(d.Checks) == 0:
+		fmt.Fprintln(w, "  none")
+	}
+	for _, c := range d.Checks {
+		required := ""
+		if c.Required {
+			required = " (required)"
+		}
+		fmt.Fprintf(w, "  %s: %s%s\n", c.Name, th.checkState(c.State), required)
+		printBuildFailures(w, th, "    ", c.Failures)
+	}
+	if _, failed := d.Errors["buildFailures"]; failed {
+		fmt.Fprintf(w, "  build failures: %s\n", unavailable("buildFailures"))
+	}
+
+	if desc := strings.TrimSpace(d.Description); desc != "" {
+		fmt.Fprintln(w, "\nDescription:")
+		for _, line := range strings.Split(desc, "\n") {
+			fmt.Fprintln(w, "  "+strings.TrimRight(line, "\r"))
+		}
+	}
+}
+
+// runPRShow runs "pr show <id>": a full summary of one pull request for triage.
+func runPRShow(args []string) {
+	logLines := flag.Int("log-lines", defaultLogLines, "Lines of each failed build task's log to show (0 for none)")
+	cfg := getConfig(args)
+	id := prArg(cfg, "show")
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	d := loadPRDetails(cfg, pr, *logLines)
+
+	out, closeOut := openOutput(cfg)
+	defer closeOut()
+	if cfg.Output == outputJSON {
+		if err := printJSON(out, d); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+		return
+	}
+	printPRDetails(out, cfg, d)
+}
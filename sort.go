@@ -0,0 +1,147 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// sortKey is one element of a --sort comparator chain.
+type sortKey struct {
+	Name string
+	Desc bool
+}
+
+// sortColumns maps sort keys to the table column they order, for header indicators.
+var sortColumns = map[string]string{
+	"id":      "PR",
+	"title":   "Title",
+	"author":  "Author",
+	"repo":    "Repo",
+	"votes":   "Votes",
+	"checks":  "Checks",
+	"created": "Created",
+	"updated": "Updated",
+}
+
+// defaultSortDesc holds the direction used when a key is given without :asc/:desc.
+var defaultSortDesc = map[string]bool{
+	"created": true,
+	"updated": true,
+}
+
+// checksRank orders check statuses from most to least in need of attention.
+var checksRank = map[string]int{
+	"Failed":       0,
+	"Unauthorized": 1,
+	"Stuck?":       2,
+	"In Progress":  3,
+	"Unknown":      4,
+	"N/A":          5,
+	"No checks":    6,
+	"Passed":       7,
+}
+
+// parseSortKeys parses a comma-separated list such as "checks,created:desc". With
+// reverse every key sorts the other way.
+func parseSortKeys(spec string, reverse bool) ([]sortKey, error) {
+	var keys []sortKey
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		name, dir, _ := strings.Cut(part, ":")
+		if _, ok := sortColumns[name]; !ok {
+			return nil, fmt.Errorf("unknown sort key %q (valid: %s)", name, strings.Join(sortKeyNames(), ", "))
+		}
+		k := sortKey{Name: name, Desc: defaultSortDesc[name]}
+		switch dir {
+		case "":
+		case "asc":
+			k.Desc = false
+		case "desc":
+			k.Desc = true
+		default:
+			return nil, fmt.Errorf("invalid direction %q for sort key %s (use asc or desc)", dir, name)
+		}
+		k.Desc = k.Desc != reverse
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func sortKeyNames() []string {
+	names := make([]string, 0, len(sortColumns))
+	for n := range sortColumns {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// voteScore ranks PRs by reviewer sentiment: approvals count up, rejections down.
+func voteScore(reviewers []reviewer) int {
+	score := 0
+	for _, r := range reviewers {
+		switch {
+		case r.Vote > 0:
+			score++
+		case r.Vote < 0:
+			score--
+		}
+	}
+	return score
+}
+
+func compareBy(key string, a, b prRecord) int {
+	switch key {
+	case "id":
+		return cmp.Compare(a.PullRequestID, b.PullRequestID)
+	case "title":
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case "author":
+		return strings.Compare(strings.ToLower(a.CreatedBy.DisplayName), strings.ToLower(b.CreatedBy.DisplayName))
+	case "repo":
+		return strings.Compare(strings.ToLower(a.Repository.Name), strings.ToLower(b.Repository.Name))
+	case "votes":
+		return cmp.Compare(voteScore(a.Reviewers), voteScore(b.Reviewers))
+	case "checks":
+		return cmp.Compare(checksRank[a.Checks], checksRank[b.Checks])
+	case "created":
+		return a.CreationDate.Compare(b.CreationDate.Time)
+	case "updated":
+		return a.Updated.Compare(b.Updated)
+	}
+	return 0
+}
+
+// sortRecords orders recs by the comparator chain in keys; ties keep their current order.
+func sortRecords(keys []sortKey, recs []prRecord) {
+	slices.SortStableFunc(recs, func(a, b prRecord) int {
+		for _, k := range keys {
+			c := compareBy(k.Name, a, b)
+			if k.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// sortHeader decorates a column header with ↑/↓ when the column is a sort key.
+func sortHeader(keys []sortKey, column string) string {
+	for _, k := range keys {
+		if sortColumns[k.Name] == column {
+			if k.Desc {
+				return column + " ↓"
+			}
+			return column + " ↑"
+		}
+	}
+	return column
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// sinceLast is the --since value selecting incremental mode.
+const sinceLast = "last"
+
+// stateFile is the on-disk record of incremental mode, one entry per org/project context.
+type stateFile struct {
+	Contexts map[string]contextState `json:"contexts"`
+}
+
+type contextState struct {
+	LastRun time.Time `json:"lastRun"`
+}
+
+func statePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "lazydevops", "state.json"), nil
+}
+
+// stateKey identifies a context by org/project and the search criteria sent to the
+// server, so runs with a different --repo or exact --target do not share a record.
+func stateKey(cfg config, criteria url.Values) string {
+	return cfg.Org + "/" + cfg.Project + "?" + criteria.Encode()
+}
+
+// loadState reads the state file; a missing file yields an empty state.
+func loadState() (stateFile, error) {
+	st := stateFile{Contexts: map[string]contextState{}}
+	p, err := statePath()
+	if err != nil {
+		return st, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(b, &st); err != nil {
+		return st, fmt.Errorf("corrupt state file %s: %w", p, err)
+	}
+	if st.Contexts == nil {
+		st.Contexts = map[string]contextState{}
+	}
+	return st, nil
+}
+
+// saveState writes the state file atomically so an interrupted run never leaves it half-written.
+func saveState(st stateFile) error {
+	p, err := statePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p, b, 0o600)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place,
+// so readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, werr := f.Write(data)
+	cerr := f.Close()
+	if err := errors.Join(werr, cerr, os.Chmod(tmp, perm)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// fetchCreatedSince lists PRs of the --status created at or after t. Completed and
+// abandoned PRs are selected by when they were closed instead.
+func fetchCreatedSince(cfg config, t time.Time) ([]pullRequest, error) {
+	rangeType := "created"
+	if cfg.Status == prStatusCompleted || cfg.Status == prStatusAbandoned {
+		rangeType = "closed"
+	}
+	return fetchInRange(cfg, cfg.Status, rangeType, t)
+}
+
+// fetchInRange lists PRs of the given status whose rangeType time (created or closed) is
+// at or after t, applying the same server-side filters as fetchActivePRs.
+func fetchInRange(cfg config, status, rangeType string, t time.Time) ([]pullRequest, error) {
+	q, err := serverCriteria(cfg)
+	if err != nil {
+		return nil, err
+	}
+	q.Set("searchCriteria.status", status)
+	q.Set("searchCriteria.queryTimeRangeType", rangeType)
+	q.Set("searchCriteria.minTime", t.UTC().Format(time.RFC3339))
+	return fetchPRs(cfg, q)
+}
+
+// stateMu serializes reading and writing the state file, which the orgs of a
+// multi-org run update concurrently.
+var stateMu sync.Mutex
+
+// updateState applies change to the state file under stateMu, so concurrent updates of
+// different contexts do not overwrite each other.
+func updateState(change func(*stateFile)) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	st, err := loadState()
+	if err != nil {
+		return err
+	}
+	change(&st)
+	return saveState(st)
+}
+
+// fetchIncremental implements --since last. With a previous run recorded it fetches the
+// active PRs in full, so titles, votes and draft flags are current, and drops the PRs
+// closed since then; otherwise it only does the full fetch. Either way the run is
+// recorded for the next one.
+//
+// The fetch is not bounded by --top, which only bounds the result.
+func fetchIncremental(cfg config) ([]pullRequest, error) {
+	stateMu.Lock()
+	st, err := loadState()
+	stateMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	criteria, err := serverCriteria(cfg)
+	if err != nil {
+		return nil, err
+	}
+	key := stateKey(cfg, criteria)
+	prev, ok := st.Contexts[key]
+	runStart := time.Now()
+
+	all := cfg
+	all.Top = 0
+	prs, err := fetchPRs(all, criteria)
+	if err != nil {
+		return nil, err
+	}
+	if ok && !prev.LastRun.IsZero() {
+		debugLog.Printf("dropping PRs of %s closed since %s", key, prev.LastRun.Format(time.RFC3339))
+		closed, err := fetchInRange(all, "all", "closed", prev.LastRun)
+		if err != nil {
+			return nil, err
+		}
+		prs = mergePRs(prs, closed)
+	} else {
+		debugLog.Printf("no previous run recorded for %s", key)
+	}
+
+	err = updateState(func(st *stateFile) {
+		st.Contexts[key] = contextState{LastRun: runStart}
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: could not save state:", err)
+	}
+	if cfg.Top > 0 && len(prs) > cfg.Top {
+		prs = prs[:cfg.Top]
+	}
+	return prs, nil
+}
+
+// mergePRs drops the closed PRs from active, matching them by ID, since a PR may close
+// between the two requests. The order of active is kept.
+func mergePRs(active, closed []pullRequest) []pullRequest {
+	gone := map[int]bool{}
+	for _, pr := range closed {
+		gone[pr.PullRequestID] = true
+	}
+	out := make([]pullRequest, 0, len(active))
+	for _, pr := range active {
+		if !gone[pr.PullRequestID] {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMergePRs(t *testing.T) {
+	active := []pullRequest{{PullRequestID: 4}, {PullRequestID: 3}, {PullRequestID: 2}}
+	closed := []pullRequest{{PullRequestID: 3}, {PullRequestID: 9}}
+	got := mergePRs(active, closed)
+	if len(got) != 2 || got[0].PullRequestID != 4 || got[1].PullRequestID != 2 {
+		t.Errorf("mergePRs = %+v, want PRs 4 and 2", got)
+	}
+}
+
+func TestStateKeyIncludesCriteria(t *testing.T) {
+	cfg := config{Org: "org", Project: "proj"}
+	all := url.Values{"searchCriteria.status": {"active"}}
+	repo := url.Values{"searchCriteria.status": {"active"}, "searchCriteria.repositoryId": {"r1"}}
+	target := url.Values{"searchCriteria.status": {"active"}, "searchCriteria.targetRefName": {"refs/heads/main"}}
+	keys := map[string]bool{}
+	for _, q := range []url.Values{all, repo, target} {
+		keys[stateKey(cfg, q)] = true
+	}
+	if len(keys) != 3 {
+		t.Errorf("stateKey collides across criteria: %v", keys)
+	}
+}
+
+// prServer serves the PR list of project proj: active for plain queries and closed for
+// queries by close time, recording the time range types requested.
+type prServer struct {
+	mu         sync.Mutex
+	active     []pullRequest
+	closed     []pullRequest
+	rangeTypes []string
+}
+
+func (s *prServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := r.URL.Query()
+	rangeType := q.Get("searchCriteria.queryTimeRangeType")
+	s.rangeTypes = append(s.rangeTypes, rangeType)
+	prs := s.active
+	switch rangeType {
+	case "":
+		if q.Get("searchCriteria.status") != prStatusActive {
+			http.Error(w, "unexpected status", http.StatusBadRequest)
+			return
+		}
+	case "closed":
+		prs = s.closed
+	default:
+		http.Error(w, fmt.Sprintf("invalid queryTimeRangeType %q", rangeType), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"value": prs, "count": len(prs)})
+}
+
+func TestFetchIncremental(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	// the server lists PRs newest first
+	s := &prServer{active: []pullRequest{
+		{PullRequestID: 2, Title: "second", Status: prStatusActive},
+		{PullRequestID: 1, Title: "first", Status: prStatusActive},
+	}}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	cfg := config{BaseURL: srv.URL, Project: "proj", Status: prStatusActive, PageSize: 100, Top: 1}
+
+	prs, err := fetchIncremental(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 || prs[0].PullRequestID != 2 {
+		t.Errorf("first run = %+v, want PR 2 bounded by --top", prs)
+	}
+
+	// PR 2 got a vote and a new title; PR 1 closed after the active list was fetched
+	s.mu.Lock()
+	s.active = []pullRequest{
+		{PullRequestID: 3, Title: "third", Status: prStatusActive},
+		{PullRequestID: 2, Title: "second, renamed", Status: prStatusActive, Reviewers: []reviewer{{ID: "r", Vote: 10}}},
+		{PullRequestID: 1, Title: "first", Status: prStatusActive},
+	}
+	s.closed = []pullRequest{{PullRequestID: 1, Status: prStatusCompleted}}
+	s.rangeTypes = nil
+	s.mu.Unlock()
+
+	cfg.Top = 0
+	prs, err = fetchIncremental(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 2 || prs[0].PullRequestID != 3 || prs[1].PullRequestID != 2 {
+		t.Fatalf("second run = %+v, want PRs 3 and 2", prs)
+	}
+	if prs[1].Title != "second, renamed" || len(prs[1].Reviewers) != 1 {
+		t.Errorf("PR 2 = %+v, want the refreshed title and vote", prs[1])
+	}
+	if len(s.rangeTypes) != 2 || s.rangeTypes[0] != "" || s.rangeTypes[1] != "closed" {
+		t.Errorf("requested time ranges %q, want a full fetch and closed", s.rangeTypes)
+	}
+}
+
+func TestUpdateStateConcurrently(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := updateState(func(st *stateFile) {
+				st.Contexts[fmt.Sprintf("org%d/proj", i)] = contextState{LastRun: time.Now()}
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	st, err := loadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.Contexts) != 8 {
+		t.Errorf("state has %d contexts, want 8", len(st.Contexts))
+	}
+}
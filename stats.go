@@ -0,0 +1,84 @@
+package main
+
+import (
+	"cmp"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// voteWaitingForAuthor is the reviewer vote meaning "waiting for author".
+const voteWaitingForAuthor = -5
+
+// authorStats aggregates the open PRs of one author.
+type authorStats struct {
+	Author   string
+	Open     int
+	TotalAge time.Duration
+	// Dated counts the PRs with a creation date, which TotalAge sums over.
+	Dated   int
+	Failing int
+	// Waiting counts PRs where a reviewer voted "waiting for author".
+	Waiting int
+}
+
+func statsByAuthor(recs []prRecord, now time.Time) []authorStats {
+	byName := map[string]*authorStats{}
+	for _, r := range recs {
+		name := r.CreatedBy.DisplayName
+		st, ok := byName[name]
+		if !ok {
+			st = &authorStats{Author: name}
+			byName[name] = st
+		}
+		st.Open++
+		if !r.CreationDate.IsZero() {
+			st.TotalAge += now.Sub(r.CreationDate.Time)
+			st.Dated++
+		}
+		if r.Checks == "Failed" {
+			st.Failing++
+		}
+		if slices.ContainsFunc(r.Reviewers, func(rv reviewer) bool { return rv.Vote == voteWaitingForAuthor }) {
+			st.Waiting++
+		}
+	}
+	out := make([]authorStats, 0, len(byName))
+	for _, st := range byName {
+		out = append(out, *st)
+	}
+	slices.SortFunc(out, func(a, b authorStats) int {
+		if c := cmp.Compare(b.Open, a.Open); c != 0 {
+			return c
+		}
+		return strings.Compare(strings.ToLower(a.Author), strings.ToLower(b.Author))
+	})
+	return out
+}
+
+// printAuthorStats renders the --stats-by-author report, busiest authors first.
+func printAuthorStats(out io.Writer, cfg config, recs []prRecord) {
+	now := time.Now()
+	w := table.NewWriter()
+	w.SetOutputMirror(out)
+	w.SetStyle(resolveTheme(cfg).Style)
+	w.AppendHeader(table.Row{"Author", "Open", "Avg age", "Failing checks", "Waiting for author"})
+	for _, st := range statsByAuthor(recs, now) {
+		w.AppendRow(table.Row{st.Author, st.Open, st.avgAge(now), st.Failing, st.Waiting})
+	}
+	w.AppendFooter(table.Row{"Total", len(recs)})
+	w.Render()
+}
+
+// avgAge renders the average age of the dated PRs, "–" when none has a creation date.
+func (st authorStats) avgAge(now time.Time) string {
+	if st.Dated == 0 {
+		return "–"
+	}
+	avg := now.Add(-st.TotalAge / time.Duration(st.Dated))
+	return strings.TrimSpace(humanize.RelTime(avg, now, "", ""))
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsByAuthorAverageAge(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	rec := func(author string, age time.Duration) prRecord {
+		r := prRecord{pullRequest: pullRequest{CreatedBy: identity{DisplayName: author}}}
+		if age > 0 {
+			r.CreationDate = apiTime{Time: now.Add(-age)}
+		}
+		return r
+	}
+	stats := statsByAuthor([]prRecord{
+		rec("Ann", 2*24*time.Hour),
+		rec("Ann", 4*24*time.Hour),
+		rec("Ann", 0),
+		rec("Bob", 0),
+	}, now)
+	want := map[string]struct {
+		open int
+		age  string
+	}{
+		"Ann": {3, "3 days"},
+		"Bob": {1, "–"},
+	}
+	for _, st := range stats {
+		w := want[st.Author]
+		if st.Open != w.open || st.avgAge(now) != w.age {
+			t.Errorf("%s: %d open, avg age %q; want %d, %q", st.Author, st.Open, st.avgAge(now), w.open, w.age)
+		}
+	}
+}
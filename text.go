@@ -0,0 +1,93 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// descriptionWidth is the maximum length of the inline description shown by --show-description.
+const descriptionWidth = 100
+
+var (
+	mdLink     = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	mdDecor    = regexp.MustCompile("[*_`~]+")
+	mdLineHead = regexp.MustCompile(`(?m)^\s*(#+|>|[-+*]\s|\d+\.\s)\s*`)
+)
+
+// plainText flattens markdown to a single line: link targets, emphasis markers,
+// headings, quotes and list bullets are dropped and whitespace is collapsed.
+func plainText(md string) string {
+	s := mdLink.ReplaceAllString(md, "$1")
+	s = mdLineHead.ReplaceAllString(s, "")
+	s = mdDecor.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// wrapTitle wraps s to width display columns, counting wide runes (CJK, emoji) as two
+// columns so table cells stay aligned, and keeps at most maxLines lines, marking a cut
+// with an ellipsis. Zero width disables wrapping; zero maxLines keeps every line.
+func wrapTitle(s string, width, maxLines int) string {
+	if width <= 0 {
+		return s
+	}
+	lines := wrapColumns(s, width)
+	if maxLines <= 0 || len(lines) <= maxLines {
+		return strings.Join(lines, "\n")
+	}
+	lines = lines[:maxLines]
+	last := lines[maxLines-1]
+	if runewidth.StringWidth(last)+1 > width {
+		last = runewidth.Truncate(last, width-1, "")
+	}
+	lines[maxLines-1] = last + "…"
+	return strings.Join(lines, "\n")
+}
+
+// wrapColumns breaks s into lines of at most width display columns at spaces, and
+// between runes for words wider than a line, such as CJK text without spaces.
+// text.WrapSoft leaves such words whole when width is odd.
+func wrapColumns(s string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	flush := func() {
+		lines = append(lines, line.String())
+		line.Reset()
+		lineWidth = 0
+	}
+	for _, word := range strings.Fields(s) {
+		w := runewidth.StringWidth(word)
+		if lineWidth > 0 && lineWidth+1+w <= width {
+			line.WriteByte(' ')
+			line.WriteString(word)
+			lineWidth += 1 + w
+			continue
+		}
+		if lineWidth > 0 {
+			flush()
+		}
+		for _, r := range word {
+			rw := runewidth.RuneWidth(r)
+			if lineWidth > 0 && lineWidth+rw > width {
+				flush()
+			}
+			line.WriteRune(r)
+			lineWidth += rw
+		}
+	}
+	if lineWidth > 0 || len(lines) == 0 {
+		flush()
+	}
+	return lines
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestWrapTitle(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		title           string
+		width, maxLines int
+		lines           int
+		cut             bool
+	}{
+		{"no wrapping", "修复登录页面的布局问题", 0, 0, 1, false},
+		{"ascii", "Fix the login page layout on small screens", 12, 0, 5, false},
+		{"cjk", "修复登录页面的布局问题", 8, 0, 3, false},
+		{"cjk odd width", "修复登录页面的布局问题", 7, 0, 4, false},
+		{"emoji", "🚀 Ship 🎉 the 🔥 release 🐛 fix", 10, 0, 4, false},
+		{"mixed", "Fix 修复登录页面 layout", 9, 0, 4, false},
+		{"cjk cut", "修复登录页面的布局问题", 8, 2, 2, true},
+		{"emoji cut", "🚀🚀🚀🚀🚀🚀🚀🚀", 6, 1, 1, true},
+		{"fits max lines", "修复登录页面", 8, 2, 2, false},
+	} {
+		got := wrapTitle(tc.title, tc.width, tc.maxLines)
+		lines := strings.Split(got, "\n")
+		if len(lines) != tc.lines {
+			t.Errorf("%s: wrapTitle = %q, want %d lines", tc.name, got, tc.lines)
+		}
+		if cut := strings.HasSuffix(got, "…"); cut != tc.cut {
+			t.Errorf("%s: wrapTitle = %q, ellipsis %v, want %v", tc.name, got, cut, tc.cut)
+		}
+		for _, l := range lines {
+			if tc.width > 0 && runewidth.StringWidth(l) > tc.width {
+				t.Errorf("%s: line %q is %d columns wide, want at most %d", tc.name, l, runewidth.StringWidth(l), tc.width)
+			}
+		}
+		if !tc.cut && strings.ReplaceAll(strings.ReplaceAll(got, "\n", ""), " ", "") != strings.ReplaceAll(tc.title, " ", "") {
+			t.Errorf("%s: wrapTitle = %q lost text of %q", tc.name, got, tc.title)
+		}
+	}
+}
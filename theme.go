@@ -0,0 +1,158 @@
+package main
+
+import (
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// theme bundles the table style with the colors used for check statuses and markers.
+type theme struct {
+	Style       table.Style
+	Checks      map[string]text.Colors
+	NoReviewers text.Colors
+	// Changed marks rows that changed since the previous --watch refresh.
+	Changed text.Colors
+	// Age colors the Created column: fresh, aging (ageAging and older) and stale
+	// (ageStale and older).
+	Age [3]text.Colors
+	// Glyphs prefixes check statuses with a shape so they remain distinguishable
+	// without relying on color.
+	Glyphs bool
+}
+
+var statusGlyphs = map[string]string{
+	"Passed":       "✔",
+	"Failed":       "✖",
+	"Unauthorized": "✖",
+	"In Progress":  "◔",
+	"Stuck?":       "⧖",
+	"No checks":    "○",
+	"Unknown":      "?",
+	"N/A":          "–",
+}
+
+var defaultChecksColors = map[string]text.Colors{
+	"Passed":       {text.FgGreen},
+	"Failed":       {text.FgRed},
+	"Unauthorized": {text.FgRed},
+	"In Progress":  {text.FgYellow},
+	"Stuck?":       {text.FgHiRed},
+}
+
+// themes are the built-in --color-theme choices.
+var themes = map[string]theme{
+	"dark": {
+		Style:       table.StyleColoredDark,
+		Checks:      defaultChecksColors,
+		NoReviewers: text.Colors{text.FgHiYellow},
+		Changed:     text.Colors{text.Bold, text.FgHiCyan},
+		Age:         [3]text.Colors{{text.FgGreen}, {text.FgYellow}, {text.FgRed}},
+	},
+	"light": {
+		Style:       table.StyleColoredBright,
+		Checks:      map[string]text.Colors{"Passed": {text.FgGreen}, "Failed": {text.FgRed}, "Unauthorized": {text.FgRed}, "In Progress": {text.FgMagenta}, "Stuck?": {text.FgRed}},
+		NoReviewers: text.Colors{text.FgMagenta},
+		Changed:     text.Colors{text.Bold, text.FgBlue},
+		Age:         [3]text.Colors{{text.FgGreen}, {text.FgMagenta}, {text.FgRed}},
+	},
+	"solarized": {
+		Style:       table.StyleColoredCyanWhiteOnBlack,
+		Checks:      map[string]text.Colors{"Passed": {text.FgHiGreen}, "Failed": {text.FgHiRed}, "Unauthorized": {text.FgHiRed}, "In Progress": {text.FgHiYellow}, "Stuck?": {text.FgHiRed}},
+		NoReviewers: text.Colors{text.FgHiYellow},
+		Changed:     text.Colors{text.Bold, text.FgHiCyan},
+		Age:         [3]text.Colors{{text.FgHiGreen}, {text.FgHiYellow}, {text.FgHiRed}},
+	},
+	"high-contrast": {
+		Style:       table.StyleBold,
+		Checks:      map[string]text.Colors{"Passed": {text.Bold, text.FgHiGreen}, "Failed": {text.Bold, text.FgHiRed}, "Unauthorized": {text.Bold, text.FgHiRed}, "In Progress": {text.Bold, text.FgHiYellow}, "Stuck?": {text.Bold, text.FgHiRed}},
+		NoReviewers: text.Colors{text.Bold, text.Underline},
+		Changed:     text.Colors{text.Bold, text.ReverseVideo},
+		Age:         [3]text.Colors{{text.Bold, text.FgHiGreen}, {text.Bold, text.FgHiYellow}, {text.Bold, text.FgHiRed}},
+		Glyphs:      true,
+	},
+}
+
+// plainTheme writes no ANSI escapes at all; used for --no-color and NO_COLOR.
+var plainTheme = theme{Style: table.StyleLight}
+
+func themeNames() []string {
+	names := make([]string, 0, len(themes))
+	for n := range themes {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// resolveTheme picks the configured theme, falling back to plain output when colors
+// are disabled. High-contrast keeps its status glyphs even without color.
+func resolveTheme(cfg config) theme {
+	th := themes[cfg.ColorTheme]
+	if cfg.NoColor {
+		plain := plainTheme
+		plain.Glyphs = th.Glyphs
+		return plain
+	}
+	return th
+}
+
+// PRs at least this old are shown as aging or stale in the Created column.
+const (
+	ageAging = 2 * 24 * time.Hour
+	ageStale = 7 * 24 * time.Hour
+)
+
+// age renders s, a rendering of a PR's creation time, in the color for a PR of age d.
+func (th theme) age(s string, d time.Duration) string {
+	i := 0
+	switch {
+	case d >= ageStale:
+		i = 2
+	case d >= ageAging:
+		i = 1
+	}
+	if len(th.Age[i]) == 0 {
+		return s
+	}
+	return th.Age[i].Sprint(s)
+}
+
+// checks renders an overall check status in the theme's colors and glyphs.
+func (th theme) checks(status string) string {
+	s := status
+	if g, ok := statusGlyphs[status]; ok && th.Glyphs {
+		s = g + " " + s
+	}
+	if c, ok := th.Checks[status]; ok {
+		return c.Sprint(s)
+	}
+	if th.Checks != nil {
+		return text.Faint.Sprint(s)
+	}
+	return s
+}
+
+// colorEnabled reports whether ANSI colors should be written to w:
+// only for terminals, and never when NO_COLOR is set.
+func colorEnabled(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func validTheme(name string) bool {
+	_, ok := themes[strings.ToLower(name)]
+	return ok
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// prComment is one comment of a pull request thread.
+type prComment struct {
+	ID int `json:"id"`
+	// ParentCommentID is the comment this one replies to; 0 for the first comment.
+	ParentCommentID int      `json:"parentCommentId"`
+	Author          identity `json:"author"`
+	Content         string   `json:"content"`
+	CommentType     string   `json:"commentType"`
+	IsDeleted       bool     `json:"isDeleted"`
+	// PublishedDate is when the comment was posted.
+	PublishedDate apiTime `json:"publishedDate"`
+}
+
+// prThread is a comment thread of a pull request.
+type prThread struct {
+	ID        int         `json:"id"`
+	Status    string      `json:"status"`
+	IsDeleted bool        `json:"isDeleted"`
+	Comments  []prComment `json:"comments"`
+	// LastUpdatedDate is when a comment was last added to or changed in the thread.
+	LastUpdatedDate apiTime `json:"lastUpdatedDate"`
+	// ThreadContext is set for threads on a file, nil for general discussion.
+	ThreadContext *struct {
+		FilePath       string        `json:"filePath"`
+		RightFileStart *filePosition `json:"rightFileStart"`
+		LeftFileStart  *filePosition `json:"leftFileStart"`
+	} `json:"threadContext"`
+}
+
+// filePosition is a position in a file of a pull request's diff.
+type filePosition struct {
+	Line int `json:"line"`
+}
+
+// location describes where t was started: "path:line" for code comments, else "".
+func (t prThread) location() string {
+	c := t.ThreadContext
+	if c == nil || c.FilePath == "" {
+		return ""
+	}
+	switch {
+	case c.RightFileStart != nil:
+		return fmt.Sprintf("%s:%d", c.FilePath, c.RightFileStart.Line)
+	case c.LeftFileStart != nil:
+		// comments on deleted lines only have a position in the old file
+		return fmt.Sprintf("%s:%d (old)", c.FilePath, c.LeftFileStart.Line)
+	}
+	return c.FilePath
+}
+
+// isSystem reports whether t was posted by Azure DevOps itself, e.g. for a vote or a
+// pushed iteration, rather than written by a person.
+func (t prThread) isSystem() bool {
+	return len(t.Comments) > 0 && strings.EqualFold(t.Comments[0].CommentType, "system")
+}
+
+// isActive reports whether t still awaits a resolution.
+func (t prThread) isActive() bool {
+	return strings.EqualFold(t.Status, "active") || strings.EqualFold(t.Status, "pending")
+}
+
+// fetchAllThreads lists every thread of pr, including deleted and system threads.
+func fetchAllThreads(ctx context.Context, cfg config, pr pullRequest) ([]prThread, error) {
+	var resp struct {
+		Value []prThread `json:"value"`
+	}
+	if err := apiClient(cfg, 15*time.Second).Get(ctx, prEndpoint(cfg, pr, "/threads"), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// fetchThreads lists the comment threads of pr, leaving out deleted and system threads.
+func fetchThreads(cfg config, pr pullRequest) ([]prThread, error) {
+	all, err := fetchAllThreads(context.Background(), cfg, pr)
+	if err != nil {
+		return nil, err
+	}
+	return commentThreads(all), nil
+}
+
+// commentThreads filters threads to those written by people and not deleted.
+func commentThreads(threads []prThread) []prThread {
+	var out []prThread
+	for _, t := range threads {
+		if !t.IsDeleted && !t.isSystem() {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// lastActivity is when pr was last updated: the newest of its creation and of its
+// threads, which include the system threads for pushes and votes.
+func lastActivity(pr pullRequest, threads []prThread) time.Time {
+	last := pr.CreationDate.Time
+	for _, t := range threads {
+		if t.LastUpdatedDate.After(last) {
+			last = t.LastUpdatedDate.Time
+		}
+	}
+	return last
+}
+
+// printThreads writes each thread with its comments, replies indented below the comment
+// they answer.
+func printThreads(w io.Writer, cfg config, threads []prThread) {
+	for i, t := range threads {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		header := fmt.Sprintf("Thread %d [%s]", t.ID, t.Status)
+		if loc := t.location(); loc != "" {
+			header += " " + loc
+		}
+		fmt.Fprintln(w, header)
+		replies := map[int][]prComment{}
+		for _, c := range t.Comments {
+			if !c.IsDeleted {
+				replies[c.ParentCommentID] = append(replies[c.ParentCommentID], c)
+			}
+		}
+		var printReplies func(parent, depth int)
+		printReplies = func(parent, depth int) {
+			for _, c := range replies[parent] {
+				indent := strings.Repeat("  ", depth+1)
+				fmt.Fprintf(w, "%s%s, %s (comment %d):\n", indent, c.Author.DisplayName, relTime(cfg, c.PublishedDate.Time), c.ID)
+				for _, line := range strings.Split(strings.TrimSpace(c.Content), "\n") {
+					fmt.Fprintln(w, indent+"  "+strings.TrimRight(line, "\r"))
+				}
+				printReplies(c.ID, depth+1)
+			}
+		}
+		printReplies(0, 0)
+	}
+}
+
+// runPRThreads runs "pr threads <id>": it lists the comment threads of a pull request.
+func runPRThreads(args []string) {
+	activeOnly := flag.Bool("active", false, "Only list threads that are not resolved yet")
+	cfg := getConfig(args)
+	id := prArg(cfg, "threads")
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	threads, err := fetchThreads(cfg, pr)
+	if err != nil {
+		log.Fatalln("Error: loading threads:", err)
+	}
+	if *activeOnly {
+		active := threads[:0]
+		for _, t := range threads {
+			if t.isActive() {
+				active = append(active, t)
+			}
+		}
+		threads = active
+	}
+
+	out, closeOut := openOutput(cfg)
+	defer closeOut()
+	switch {
+	case cfg.Output == outputJSON:
+		if err := printJSON(out, orEmpty(threads)); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case len(threads) == 0:
+		fmt.Fprintf(out, "PR #%d has no comment threads.\n", id)
+	default:
+		printThreads(out, cfg, threads)
+	}
+}
+
+// runPRComment runs "pr comment <id>": it replies to --thread, or starts a new thread.
+func runPRComment(args []string) {
+	threadID := flag.Int("thread", 0, "Thread to reply to (default: start a new thread)")
+	message := flag.String("message", "", "Text of the comment (markdown)")
+	cfg := getConfig(args)
+	id := prArg(cfg, "comment")
+	if strings.TrimSpace(*message) == "" {
+		failUsage("--message is required")
+	}
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+
+	if *threadID == 0 {
+		body := map[string]any{
+			"status":   "active",
+			"comments": []map[string]any{{"parentCommentId": 0, "content": *message, "commentType": "text"}},
+		}
+		var t prThread
+		if err := apiSend(cfg, http.MethodPost, prEndpoint(cfg, pr, "/threads"), body, &t); err != nil {
+			log.Fatalln("Error: creating the thread:", err)
+		}
+		fmt.Printf("Started thread %d on PR #%d\n", t.ID, id)
+		return
+	}
+	// Replies answer the thread's first comment, as the web UI does.
+	body := map[string]any{"parentCommentId": 1, "content": *message, "commentType": "text"}
+	endpoint := prEndpoint(cfg, pr, fmt.Sprintf("/threads/%d/comments", *threadID))
+	if err := apiSend(cfg, http.MethodPost, endpoint, body, nil); err != nil {
+		log.Fatalln("Error: replying:", err)
+	}
+	fmt.Printf("Replied to thread %d on PR #%d\n", *threadID, id)
+}
+
+// runPRResolve runs "pr resolve <id> --thread N": it marks the thread as resolved, or
+// active again with --reopen.
+func runPRResolve(args []string) {
+	threadID := flag.Int("thread", 0, "Thread to resolve or reopen")
+	reopen := flag.Bool("reopen", false, "Reopen the thread instead of resolving it")
+	cfg := getConfig(args)
+	id := prArg(cfg, "resolve")
+	if *threadID <= 0 {
+		failUsage("--thread is required (see lazydevops pr threads <id>)")
+	}
+	pr, err := fetchPR(cfg, id)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	// "fixed" is what the web UI's Resolve button sets.
+	status, done := "fixed", "Resolved"
+	if *reopen {
+		status, done = "active", "Reopened"
+	}
+	endpoint := prEndpoint(cfg, pr, fmt.Sprintf("/threads/%d", *threadID))
+	if err := apiSend(cfg, http.MethodPatch, endpoint, map[string]any{"status": status}, nil); err != nil {
+		log.Fatalln("Error: updating the thread:", err)
+	}
+	fmt.Printf("%s thread %d on PR #%d\n", done, *threadID, id)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	tuiSelected = lipgloss.NewStyle().Reverse(true)
+	tuiBold     = lipgloss.NewStyle().Bold(true)
+	tuiFaint    = lipgloss.NewStyle().Faint(true)
+)
+
+// tuiModel is the state of the --tui screen: the PR list on top, the selected PR's
+// details below it.
+type tuiModel struct {
+	cfg    config
+	th     theme
+	recs   []prRecord
+	cursor int
+	// offset is the index of the first visible list row.
+	offset        int
+	width, height int
+	loading       bool
+	loaded        time.Time
+	status        string
+}
+
+// recordsMsg delivers the result of a (re)load.
+type recordsMsg struct {
+	recs   []prRecord
+	failed int
+	err    error
+}
+
+// runTUI runs the interactive mode until the user quits.
+func runTUI(cfg config) error {
+	m := tuiModel{cfg: cfg, th: resolveTheme(cfg), loading: true}
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+// loadRecords fetches and enriches the PRs in the background, like a normal run.
+func loadRecords(cfg config) tea.Cmd {
+	return func() tea.Msg {
+		prs, me, err := fetchSelected(cfg)
+		if err != nil {
+			return recordsMsg{err: err}
+		}
+		errs := newEnrichErrors()
+		recs := finishRecords(cfg, prs, me, errs)
+		return recordsMsg{recs: recs, failed: errs.len()}
+	}
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return loadRecords(m.cfg)
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case recordsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = "Refresh failed: " + shortError(msg.err)
+			break
+		}
+		m.recs, m.loaded = msg.recs, time.Now()
+		m.cursor = min(m.cursor, max(len(m.recs)-1, 0))
+		m.status = ""
+		if msg.failed > 0 {
+			m.status = fmt.Sprintf("%d PRs could not be fully loaded (run with --debug for details)", msg.failed)
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			m.cursor = max(m.cursor-1, 0)
+		case "down", "j":
+			m.cursor = max(min(m.cursor+1, len(m.recs)-1), 0)
+		case "pgup":
+			m.cursor = max(m.cursor-m.listHeight(), 0)
+		case "pgdown":
+			m.cursor = max(min(m.cursor+m.listHeight(), len(m.recs)-1), 0)
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = max(len(m.recs)-1, 0)
+		case "r":
+			if !m.loading {
+				m.loading = true
+				return m, loadRecords(m.cfg)
+			}
+		case "o", "enter":
+			if m.cursor < len(m.recs) {
+				if href := m.recs[m.cursor].Links.Web.Href; href != "" {
+					if err := openBrowser(href); err != nil {
+						m.status = "Could not open the browser: " + err.Error()
+					}
+				}
+			}
+		}
+	}
+	m.scroll()
+	return m, nil
+}
+
+// listHeight is the number of PR rows shown; the detail pane gets the rest.
+func (m tuiModel) listHeight() int {
+	return max((m.height-3)/2, 3)
+}
+
+// scroll keeps the cursor inside the visible part of the list.
+func (m *tuiModel) scroll() {
+	h := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+}
+
+func (m tuiModel) View() string {
+	if m.width == 0 {
+		return ""
+	}
+	var b strings.Builder
+	scope := m.cfg.Org + "/" + strings.Join(m.cfg.Projects, ",")
+	if m.cfg.multiOrg() {
+		orgs := make([]string, len(m.cfg.Orgs))
+		for i, c := range m.cfg.Orgs {
+			orgs[i] = c.Org
+		}
+		scope = strings.Join(orgs, ", ")
+	}
+	header := fmt.Sprintf("LazyDevOps  %s  %s", scope, msgN(msgSummary, len(m.recs)))
+	switch {
+	case m.loading:
+		header += "  loading…"
+	case !m.loaded.IsZero():
+		header += "  updated " + m.loaded.Format("15:04:05")
+	}
+	b.WriteString(tuiBold.Render(runewidth.Truncate(header, m.width, "…")) + "\n")
+
+	h := m.listHeight()
+	for i := m.offset; i < m.offset+h; i++ {
+		if i < len(m.recs) {
+			b.WriteString(m.row(i))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(tuiFaint.Render(strings.Repeat("─", m.width)) + "\n")
+
+	detail := m.detail()
+	room := max(m.height-h-3, 0)
+	if len(detail) > room {
+		detail = detail[:room]
+	}
+	for _, l := range detail {
+		b.WriteString(l + "\n")
+	}
+	for range room - len(detail) {
+		b.WriteString("\n")
+	}
+
+	footer := "↑/↓ move  o open  r refresh  q quit"
+	if m.status != "" {
+		footer += "  " + m.status
+	}
+	b.WriteString(tuiFaint.Render(runewidth.Truncate(footer, m.width, "…")))
+	return b.String()
+}
+
+// row renders list entry i like --oneline, cut to the screen width.
+func (m tuiModel) row(i int) string {
+	r := m.recs[i]
+	status := r.Checks
+	if g, ok := statusGlyphs[status]; ok && m.th.Glyphs {
+		status = g + " " + status
+	}
+	prefix := fmt.Sprintf("#%d [%s] %s ", r.PullRequestID, status, summarizeVotesTyped(r.Reviewers))
+	rest := fmt.Sprintf("%s (%s)", r.Title, r.CreatedBy.DisplayName)
+	rest = runewidth.Truncate(rest, max(m.width-runewidth.StringWidth(prefix), 0), "…")
+	if i == m.cursor {
+		line := prefix + rest
+		return tuiSelected.Render(line + strings.Repeat(" ", max(m.width-runewidth.StringWidth(line), 0)))
+	}
+	return fmt.Sprintf("#%d [%s] %s %s", r.PullRequestID, m.th.checks(r.Checks), summarizeVotesTyped(r.Reviewers), rest)
+}
+
+// detail returns the lines of the detail pane for the selected PR.
+func (m tuiModel) detail() []string {
+	if m.cursor >= len(m.recs) {
+		if m.loading {
+			return []string{"Loading pull requests…"}
+		}
+		return []string{msg(msgNoMatches, statusLabel(m.cfg.Status))}
+	}
+	r := m.recs[m.cursor]
+	fit := func(s string) string { return runewidth.Truncate(s, m.width, "…") }
+	lines := []string{
+		tuiBold.Render(fit(fmt.Sprintf("#%d %s", r.PullRequestID, r.Title))),
+		fit(fmt.Sprintf("Author: %s   Repo: %s   Created: %s", r.CreatedBy.DisplayName, r.Repository.Name, relTime(m.cfg, r.CreationDate.Time))),
+	}
+	branches := refShort(r.SourceRefName) + " -> " + refShort(r.TargetRefName)
+	if r.nonDefaultTarget() {
+		branches += " ⚠ default branch is " + refShort(r.DefaultBranch)
+	}
+	lines = append(lines, fit("Branches: "+branches))
+	lines = append(lines, "Checks: "+m.th.checks(r.Checks)+"   Votes: "+summarizeVotesTyped(r.Reviewers))
+	if len(r.Reviewers) > 0 {
+		lines = append(lines, "Reviewers:")
+		for _, rv := range r.Reviewers {
+			lines = append(lines, fit(fmt.Sprintf("  %s (%s)", rv.DisplayName, voteLabel(rv))))
+		}
+	}
+	if desc := plainText(r.Description); desc != "" {
+		lines = append(lines, "Description:")
+		lines = append(lines, strings.Split(wrapTitle(desc, max(m.width-2, 2), 0), "\n")...)
+	}
+	if r.Links.Web.Href != "" {
+		lines = append(lines, fit("URL: "+r.Links.Web.Href))
+	}
+	return lines
+}
+
+// voteLabel describes a reviewer's vote in words.
+func voteLabel(r reviewer) string {
+	switch {
+	case r.HasDeclined:
+		return "declined"
+	case r.Vote >= 10:
+		return "approved"
+	case r.Vote > 0:
+		return "approved with suggestions"
+	case r.Vote == voteWaitingForAuthor:
+		return "waiting for author"
+	case r.Vote < 0:
+		return "rejected"
+	}
+	return "no vote"
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// defaultWatchInterval is used when --watch is given without a duration.
+const defaultWatchInterval = time.Minute
+
+// watchFlag is the --watch flag: a duration that may be left out ("--watch" alone
+// means defaultWatchInterval, "--watch=30s" sets it). Zero means watch mode is off.
+type watchFlag time.Duration
+
+func (w *watchFlag) String() string { return time.Duration(*w).String() }
+
+func (w *watchFlag) IsBoolFlag() bool { return true }
+
+func (w *watchFlag) Set(v string) error {
+	if on, err := strconv.ParseBool(v); err == nil {
+		*w = 0
+		if on {
+			*w = watchFlag(defaultWatchInterval)
+		}
+		return nil
+	}
+	d, err := parseAge(v)
+	if err != nil || d < 5*time.Second {
+		return fmt.Errorf("expected a refresh interval of at least 5s, e.g. --watch=30s")
+	}
+	*w = watchFlag(d)
+	return nil
+}
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// runWatch redraws the PR list every cfg.Watch until interrupted, marking PRs that
+// are new or whose checks or votes changed since the previous refresh. With --notify
+// it also sends desktop notifications for new PRs and for votes and failed checks on
+// my PRs.
+func runWatch(cfg config) {
+	var prev map[prKey]string
+	var snapshots map[prKey]prSnapshot
+	var me userIdentity
+	if cfg.Notify {
+		var err error
+		if me, err = resolveMe(cfg); err != nil {
+			log.Fatalln("Error: resolving your identity for --notify:", err)
+		}
+	}
+	for {
+		prs, selMe, err := fetchSelected(cfg)
+		var recs []prRecord
+		if err == nil {
+			if selMe.ID == "" {
+				selMe = me
+			}
+			recs = finishRecords(cfg, prs, selMe, newEnrichErrors())
+		}
+		fmt.Print(clearScreen)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		} else {
+			next := make(map[prKey]string, len(recs))
+			for i, r := range recs {
+				state := r.Checks + " " + summarizeVotesTyped(r.Reviewers)
+				recs[i].Changed = prev != nil && prev[keyOf(r.pullRequest)] != state
+				next[keyOf(r.pullRequest)] = state
+			}
+			prev = next
+			if cfg.Notify {
+				for _, n := range prEvents(snapshots, recs, me) {
+					if err := notify(n); err != nil {
+						fmt.Fprintln(os.Stderr, "Warning: sending a notification:", err)
+					}
+				}
+				snapshots = make(map[prKey]prSnapshot, len(recs))
+				for _, r := range recs {
+					snapshots[keyOf(r.pullRequest)] = snapshotOf(r)
+				}
+			}
+			switch {
+			case cfg.Output == outputTemplate:
+				if err := printTemplate(os.Stdout, cfg.Template, recs); err != nil {
+					fmt.Fprintln(os.Stderr, "Error:", err)
+				}
+			case cfg.Oneline:
+				printOneline(os.Stdout, cfg, recs)
+			default:
+				printTable(os.Stdout, cfg, recs)
+			}
+		}
+		fmt.Printf("Refreshed %s, every %s (● changed since the last refresh). Press Ctrl+C to quit.\n",
+			time.Now().Format("15:04:05"), time.Duration(cfg.Watch))
+		time.Sleep(time.Duration(cfg.Watch))
+	}
+}
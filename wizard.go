@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// runInitWizard interactively asks for org, project and PAT, checks that they work
+// and saves them to the config file.
+func runInitWizard(cfg config, fc fileConfig) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return errors.New("--init needs an interactive terminal; edit the config file or pass flags instead")
+	}
+	in := bufio.NewReader(os.Stdin)
+
+	fmt.Println("LazyDevOps setup. Press Enter to keep the value in brackets.")
+	fc.Org = prompt(in, "Azure DevOps organization", fc.Org)
+	fc.Project = prompt(in, "Project", fc.Project)
+	if fc.Org == "" || fc.Project == "" {
+		return errors.New("organization and project are required")
+	}
+
+	fmt.Println("Personal Access Token with Code (Read) scope. Leave empty to use " + envVarPrimaryPAT + " instead.")
+	fmt.Print("PAT (input hidden): ")
+	pat, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	if p := strings.TrimSpace(string(pat)); p != "" {
+		fc.Pat = p
+	}
+
+	cfg.Org, cfg.Project = fc.Org, fc.Project
+	cfg.Pat = os.Getenv(envVarPrimaryPAT)
+	if fc.Pat != "" {
+		cfg.Pat = fc.Pat
+	}
+	if cfg.Pat == "" {
+		return errors.New("no PAT given and " + envVarPrimaryPAT + " is not set")
+	}
+
+	fmt.Print("Checking connectivity... ")
+	q := url.Values{}
+	q.Set("searchCriteria.status", "active")
+	if _, err := fetchPRPage(cfg, q, 0, 1); err != nil {
+		fmt.Println("failed:", err)
+		if !confirm("Save the configuration anyway?") {
+			return errors.New("setup cancelled")
+		}
+	} else {
+		fmt.Println("ok")
+	}
+
+	values := map[string]string{"org": fc.Org, "project": fc.Project}
+	if fc.Pat != "" {
+		values["pat"] = fc.Pat
+	}
+	p, err := setFileConfig(values)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Saved", p)
+	fmt.Println("You're all set. Run:")
+	fmt.Println("  lazydevops")
+	return nil
+}
+
+// prompt asks for a value, returning def when the answer is empty.
+func prompt(in *bufio.Reader, label, def string) string {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", label, def)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+	line, _ := in.ReadString('\n')
+	if v := strings.TrimSpace(line); v != "" {
+		return v
+	}
+	return def
+}
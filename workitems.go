@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"LazyDevOps/pkg/azdo"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// workItemFields are the fields shown for a work item.
+var workItemFields = []string{"System.Id", "System.WorkItemType", "System.Title", "System.State", "System.AssignedTo", "System.IterationPath"}
+
+// doneStates are left out by "wit list" unless --state is given. Processes name their
+// final states differently; these cover the built-in ones.
+var doneStates = []string{"Closed", "Done", "Removed"}
+
+// workItem is a work item as shown by "wit list".
+type workItem struct {
+	ID            int    `json:"id"`
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	State         string `json:"state"`
+	AssignedTo    string `json:"assignedTo,omitempty"`
+	IterationPath string `json:"iterationPath"`
+	URL           string `json:"url"`
+}
+
+// newWorkItem picks the shown fields of wi.
+func newWorkItem(cfg config, wi azdo.WorkItem) workItem {
+	return workItem{
+		ID:            wi.ID,
+		Type:          wi.Field("System.WorkItemType"),
+		Title:         wi.Field("System.Title"),
+		State:         wi.Field("System.State"),
+		AssignedTo:    wi.Field("System.AssignedTo"),
+		IterationPath: wi.Field("System.IterationPath"),
+		URL:           apiClient(cfg, 0).WebURL(cfg.Project, fmt.Sprintf("_workitems/edit/%d", wi.ID)),
+	}
+}
+
+// wiqlString quotes s as a WIQL string literal.
+func wiqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// wiqlList quotes values as a WIQL list, e.g. ('Active', 'New').
+func wiqlList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = wiqlString(v)
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
+
+// workItemListQuery builds the WIQL query of "wit list". assignedTo is "me" (@me), a
+// user, or "" for anyone.
+func workItemListQuery(assignedTo string, states, types []string) string {
+	conds := []string{"[System.TeamProject] = @project"}
+	switch {
+	case strings.EqualFold(assignedTo, "me"):
+		conds = append(conds, "[System.AssignedTo] = @me")
+	case assignedTo != "":
+		conds = append(conds, "[System.AssignedTo] = "+wiqlString(assignedTo))
+	}
+	if len(states) > 0 {
+		conds = append(conds, "[System.State] IN "+wiqlList(states))
+	} else {
+		conds = append(conds, "[System.State] NOT IN "+wiqlList(doneStates))
+	}
+	if len(types) > 0 {
+		conds = append(conds, "[System.WorkItemType] IN "+wiqlList(types))
+	}
+	return "SELECT [System.Id] FROM WorkItems WHERE " + strings.Join(conds, " AND ") + " ORDER BY [System.ChangedDate] DESC"
+}
+
+// printWorkItems writes one row per work item; the Assigned to column is left out when
+// all of them are assigned to the same person.
+func printWorkItems(w io.Writer, cfg config, title string, items []workItem) {
+	th := resolveTheme(cfg)
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(th.Style)
+	t.SetTitle(title)
+	sameAssignee := true
+	for _, wi := range items {
+		sameAssignee = sameAssignee && wi.AssignedTo == items[0].AssignedTo
+	}
+	if sameAssignee {
+		t.AppendHeader(table.Row{"ID", "Type", "Title", "State", "Iteration"})
+	} else {
+		t.AppendHeader(table.Row{"ID", "Type", "Title", "State", "Assigned to", "Iteration"})
+	}
+	for _, wi := range items {
+		row := table.Row{wi.ID, wi.Type, truncate(wi.Title, 80), wi.State}
+		if !sameAssignee {
+			row = append(row, wi.AssignedTo)
+		}
+		t.AppendRow(append(row, wi.IterationPath))
+	}
+	t.Render()
+}
+
+// runWitList runs "wit list": the project's open work items assigned to me, or to
+// --assigned-to, optionally of some states and types.
+func runWitList(args []string) {
+	assignedTo := flag.String("assigned-to", "me", "Only list work items assigned to this user (email or display name); me is you, any lists everyone's")
+	var states, types stringList
+	flag.Var(&states, "state", "Only list work items in this state (repeatable or comma-separated; default: all but Closed, Done and Removed)")
+	flag.Var(&types, "type", "Only list work items of this type, e.g. Bug (repeatable or comma-separated)")
+	cfg := getConfig(args)
+	if cfg.multiProject() {
+		failUsage("wit list needs a single --project")
+	}
+	who := *assignedTo
+	if strings.EqualFold(who, "any") {
+		who = ""
+	}
+
+	c := apiClient(cfg, 30*time.Second)
+	ids, err := c.QueryWIQL(context.Background(), cfg.Project, workItemListQuery(who, states, types), cfg.Top)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	wis, err := c.WorkItems(context.Background(), cfg.Project, ids, workItemFields)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+	items := make([]workItem, 0, len(wis))
+	for _, wi := range wis {
+		items = append(items, newWorkItem(cfg, wi))
+	}
+
+	out, closeOut := openOutput(cfg)
+	defer closeOut()
+	switch {
+	case cfg.Output == outputJSON:
+		if err := printJSON(out, orEmpty(items)); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case len(items) == 0:
+		fmt.Fprintln(out, "No work items found.")
+	default:
+		printWorkItems(out, cfg, fmt.Sprintf("Work items in %s", cfg.Project), items)
+	}
+}
+
+// queryRow is a work item returned by "wit query": the fields the query selects, by
+// reference name.
+type queryRow struct {
+	ID     int            `json:"id"`
+	URL    string         `json:"url"`
+	Fields map[string]any `json:"fields"`
+}
+
+// printQueryRows writes one row per work item with the columns the query selects.
+func printQueryRows(w io.Writer, cfg config, title string, columns []azdo.FieldRef, items []azdo.WorkItem) {
+	th := resolveTheme(cfg)
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(th.Style)
+	t.SetTitle(title)
+	header := make(table.Row, len(columns))
+	for i, col := range columns {
+		header[i] = col.Name
+	}
+	t.AppendHeader(header)
+	for _, wi := range items {
+		row := make(table.Row, len(columns))
+		for i, col := range columns {
+			row[i] = truncate(wi.Field(col.ReferenceName), 60)
+		}
+		t.AppendRow(row)
+	}
+	t.Render()
+}
+
+// runWitQuery runs "wit query": it runs a WIQL query given with --wiql, or the saved
+// query at --saved, and lists the work items with the columns the query selects.
+func runWitQuery(args []string) {
+	wiql := flag.String("wiql", "", "WIQL query to run, e.g. \"SELECT [System.Id], [System.Title] FROM WorkItems WHERE [System.State] = 'Active'\"")
+	saved := flag.String("saved", "", "Path of a saved query to run, e.g. \"Shared Queries/Active bugs\", or its ID")
+	cfg := getConfig(args)
+	if (*wiql == "") == (*saved == "") {
+		failUsage("wit query needs either --wiql or --saved")
+	}
+	if cfg.multiProject() {
+		failUsage("wit query needs a single --project")
+	}
+
+	ctx := context.Background()
+	c := apiClient(cfg, 30*time.Second)
+	var res *azdo.QueryResult
+	title := "Query results"
+	if *saved != "" {
+		q, err := c.SavedQuery(ctx, cfg.Project, *saved)
+		if err != nil {
+			log.Fatalf("Error: finding query %q: %v\n", *saved, err)
+		}
+		if q.IsFolder {
+			failUsage(fmt.Sprintf("--saved: %q is a query folder", q.Path))
+		}
+		title = q.Path
+		res, err = c.RunQuery(ctx, cfg.Project, q.ID, cfg.Top)
+		if err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	} else {
+		var err error
+		if res, err = c.RunWIQL(ctx, cfg.Project, *wiql, cfg.Top); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	}
+	columns := res.Columns
+	if len(columns) == 0 {
+		columns = []azdo.FieldRef{{ReferenceName: "System.Id", Name: "ID"}, {ReferenceName: "System.Title", Name: "Title"}}
+	}
+	fields := make([]string, len(columns))
+	for i, col := range columns {
+		fields[i] = col.ReferenceName
+	}
+	wis, err := c.WorkItems(ctx, cfg.Project, res.IDs, fields)
+	if err != nil {
+		log.Fatalln("Error: ", err)
+	}
+
+	out, closeOut := openOutput(cfg)
+	defer closeOut()
+	switch {
+	case cfg.Output == outputJSON:
+		rows := make([]queryRow, 0, len(wis))
+		for _, wi := range wis {
+			rows = append(rows, queryRow{
+				ID:     wi.ID,
+				URL:    c.WebURL(cfg.Project, fmt.Sprintf("_workitems/edit/%d", wi.ID)),
+				Fields: wi.Fields,
+			})
+		}
+		if err := printJSON(out, orEmpty(rows)); err != nil {
+			log.Fatalln("Error: ", err)
+		}
+	case len(wis) == 0:
+		fmt.Fprintln(out, "The query returned no work items.")
+	default:
+		printQueryRows(out, cfg, title, columns, wis)
+	}
+}